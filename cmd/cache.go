@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bitcs/commet/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached commit messages",
+	Long: `Generated commit messages are cached by a hash of the diff, prompt, provider,
+model and generation options, so rerunning commet after an aborted commit is instant.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached commit messages",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := cache.Open()
+		if err != nil {
+			fmt.Printf("Error opening cache: %v\n", err)
+			return
+		}
+
+		removed, err := store.Clear()
+		if err != nil {
+			fmt.Printf("Error clearing cache: %v\n", err)
+			return
+		}
+		fmt.Printf("Removed %d cached message(s)\n", removed)
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove expired cached commit messages",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := cache.Open()
+		if err != nil {
+			fmt.Printf("Error opening cache: %v\n", err)
+			return
+		}
+
+		removed, err := store.Prune()
+		if err != nil {
+			fmt.Printf("Error pruning cache: %v\n", err)
+			return
+		}
+		fmt.Printf("Removed %d expired message(s)\n", removed)
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache location and size",
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := cache.Open()
+		if err != nil {
+			fmt.Printf("Error opening cache: %v\n", err)
+			return
+		}
+
+		stats, err := store.Stats()
+		if err != nil {
+			fmt.Printf("Error reading cache: %v\n", err)
+			return
+		}
+
+		fmt.Printf("Location: %s\n", stats.Dir)
+		fmt.Printf("Entries:  %d (%d expired)\n", stats.Entries, stats.Expired)
+		fmt.Printf("Size:     %.1f KiB\n", float64(stats.Bytes)/1024)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/changelog"
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/llm"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changelogVersion  string
+	changelogWrite    bool
+	changelogFile     string
+	changelogNoAI     bool
+	changelogPRTitles bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog [from..to]",
+	Short: "Generate a Keep a Changelog section from commit history",
+	Long: `Generate a Markdown changelog section in the Keep a Changelog format from the
+commits in a range. The range defaults to the commits since the latest tag.
+Conventional commits are sorted by their type; other messages are classified
+and reworded by the AI.
+
+Examples:
+  commet changelog                         # Commits since the latest tag
+  commet changelog v1.2.0..v1.3.0          # An explicit range
+  commet changelog --version 1.3.0 -w      # Prepend a release to CHANGELOG.md
+  commet changelog --pr-titles             # Use merged pull request titles`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		from, to := "", "HEAD"
+		if len(args) == 1 {
+			if from, to, err = splitRange(args[0]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		} else if tag, err := git.GetLatestTag("HEAD"); err == nil {
+			from = tag
+		}
+
+		getCommits := git.GetCommits
+		if changelogPRTitles {
+			// A merged pull request stands in for the commits it merged
+			getCommits = git.GetFirstParentCommits
+		}
+		commits, err := getCommits(from, to)
+		if err != nil {
+			color.Red("Error reading commits: %v\n", err)
+			return
+		}
+
+		var service llm.Generator
+		if !changelogNoAI && (cfg.AI.APIKey != "" || !cfg.AI.Provider.RequiresAPIKey()) {
+			service, err = newGenerator(cfg, newRepository(), "changelog", false)
+			if err != nil {
+				fmt.Printf("Error creating LLM service: %v\n", err)
+				return
+			}
+		}
+
+		entries := changelogEntries(cfg, commits, service, changelogPRTitles)
+		if service != nil && service.Usage().Total() > 0 {
+			color.Cyan("Usage: %s", service.Usage())
+		}
+		if len(entries) == 0 {
+			color.Yellow("No user-facing changes found in %d commit(s).", len(commits))
+			return
+		}
+
+		section := changelog.Render(changelogVersion, time.Now(), entries)
+		if !changelogWrite {
+			fmt.Print(section)
+			return
+		}
+
+		path := changelogFile
+		if !filepath.IsAbs(path) {
+			if top, err := git.TopLevel(); err == nil {
+				path = filepath.Join(top, path)
+			}
+		}
+		if err := changelog.Prepend(path, section); err != nil {
+			color.Red("Error updating changelog: %v\n", err)
+			return
+		}
+		color.Green("Added %d changelog entries to %s", len(entries), path)
+	},
+}
+
+// splitRange splits a from..to revision range, where a missing end means
+// HEAD. The three-dot form selects commits on either side rather than a
+// history, so it is refused.
+func splitRange(arg string) (string, string, error) {
+	if strings.Contains(arg, "...") {
+		return "", "", fmt.Errorf("symmetric range %s is not supported; use from..to", arg)
+	}
+	from, to, _ := strings.Cut(arg, "..")
+	if to == "" {
+		to = "HEAD"
+	}
+	return from, to, nil
+}
+
+// changelogEntries classifies commits by their conventional type and asks
+// the model about the rest when service is not nil. Merged pull requests are
+// listed by title when prTitles is set. Commits the model could not classify
+// are listed under Changed.
+func changelogEntries(cfg *config.Config, commits []git.Commit, service llm.Generator, prTitles bool) []changelog.Entry {
+	var (
+		entries []changelog.Entry
+		pending []int
+		unknown []string
+	)
+	for _, c := range commits {
+		if changelog.IsMerge(c) || (changelog.IsPullRequestMerge(c) && !prTitles) {
+			continue
+		}
+		entry, ok := changelog.Classify(c)
+		if !ok {
+			pending = append(pending, len(entries))
+			unknown = append(unknown, c.Message())
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(pending) > 0 && service != nil {
+		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Classifying %d non-conventional commit(s) using %s...", len(pending), cfg.AI.Provider)
+		s.Start()
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+		defer cancel()
+
+		classes, err := service.ClassifyCommits(ctx, unknown)
+		s.Stop()
+		if err != nil {
+			// classes is empty, so the commits fall back to Changed below
+			color.Yellow("Could not classify commits with AI, listing them under Changed: %v", err)
+		}
+
+		for i := range classes {
+			idx := pending[i]
+			if section, ok := changelog.ParseSection(classes[i].Section); ok {
+				entries[idx].Section = section
+			}
+			if classes[i].Entry != "" {
+				entries[idx].Text = classes[i].Entry
+			}
+			entries[idx].Breaking = entries[idx].Breaking || classes[i].Breaking
+		}
+	}
+
+	var result []changelog.Entry
+	for _, e := range entries {
+		if e.Section == "" {
+			// Without a classification a change is reported rather than lost
+			e.Section = changelog.Changed
+		}
+		if e.Section != changelog.Skip {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", "", "Version for the section heading (default: Unreleased)")
+	changelogCmd.Flags().BoolVarP(&changelogWrite, "write", "w", false, "Prepend the section to the changelog file")
+	changelogCmd.Flags().StringVar(&changelogFile, "file", "CHANGELOG.md", "Changelog file, relative to the repository root")
+	changelogCmd.Flags().BoolVar(&changelogNoAI, "no-ai", false, "Do not use AI for non-conventional commits")
+	changelogCmd.Flags().BoolVar(&changelogPRTitles, "pr-titles", false, "List merged pull requests by their titles instead of their commits")
+	rootCmd.AddCommand(changelogCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitcs/commet/internal/changelog"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/llm"
+	"github.com/bitcs/commet/internal/testutil"
+)
+
+// failingClassifier is a generator whose commit classification always fails
+type failingClassifier struct {
+	llm.Generator
+}
+
+func (failingClassifier) ClassifyCommits(ctx context.Context, messages []string) ([]llm.CommitClass, error) {
+	return nil, errors.New("model unavailable")
+}
+
+func TestChangelogEntriesFallBackWhenAIFails(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: "1111111111", Subject: "feat: add export"},
+		{Hash: "2222222222", Subject: "Tweak the report layout"},
+	}
+
+	entries := changelogEntries(testConfig(), commits, failingClassifier{}, false)
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(entries), entries)
+	}
+	if entries[0].Section != changelog.Added || entries[1].Section != changelog.Changed {
+		t.Errorf("sections = %s, %s, want Added, Changed", entries[0].Section, entries[1].Section)
+	}
+	if entries[1].Text != "Tweak the report layout" {
+		t.Errorf("fallback entry text = %q", entries[1].Text)
+	}
+}
+
+func TestChangelogPullRequestTitlesReplaceMergedCommits(t *testing.T) {
+	newTestRepo(t)
+	base := testutil.Git(t, "rev-parse", "HEAD")
+
+	testutil.Git(t, "checkout", "-q", "-b", "feature")
+	testutil.WriteFile(t, "a.txt", "a1\n")
+	testutil.Git(t, "commit", "-q", "-am", "fix: first step")
+	testutil.WriteFile(t, "a.txt", "a2\n")
+	testutil.Git(t, "commit", "-q", "-am", "fix: second step")
+	testutil.Git(t, "checkout", "-q", "-")
+	testutil.WriteFile(t, "b.txt", "b1\n")
+	testutil.Git(t, "commit", "-q", "-am", "fix: direct change")
+	testutil.Git(t, "merge", "-q", "--no-ff", "feature", "-m", "Merge pull request #5 from me/feature", "-m", "feat: add the feature")
+
+	commits, err := git.GetFirstParentCommits(base, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := changelogEntries(testConfig(), commits, nil, true)
+
+	var texts []string
+	for _, e := range entries {
+		texts = append(texts, string(e.Section)+": "+e.Text)
+	}
+	want := []string{"Fixed: Direct change", "Added: Add the feature (#5)"}
+	if len(texts) != len(want) || texts[0] != want[0] || texts[1] != want[1] {
+		t.Errorf("entries = %q, want %q", texts, want)
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	tests := []struct{ arg, from, to string }{
+		{"v1.0.0..v1.1.0", "v1.0.0", "v1.1.0"},
+		{"v1.0.0..", "v1.0.0", "HEAD"},
+		{"v1.0.0", "v1.0.0", "HEAD"},
+	}
+	for _, tt := range tests {
+		from, to, err := splitRange(tt.arg)
+		if err != nil || from != tt.from || to != tt.to {
+			t.Errorf("splitRange(%q) = %q, %q, %v, want %q, %q", tt.arg, from, to, err, tt.from, tt.to)
+		}
+	}
+
+	if _, _, err := splitRange("main...feature"); err == nil {
+		t.Error("splitRange() of a three-dot range succeeded, want error")
+	}
+}
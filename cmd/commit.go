@@ -11,8 +11,7 @@ import (
 	"github.com/atotto/clipboard"
 	"github.com/bitcs/commet/internal/config"
 	"github.com/bitcs/commet/internal/git"
-	"github.com/bitcs/commet/internal/llm"
-	"github.com/bitcs/commet/internal/ui"
+	"github.com/bitcs/commet/internal/review"
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -22,6 +21,9 @@ var (
 	interactiveMode bool
 	directCommit    bool
 	useAI           bool
+	noCache         bool
+	reviewGate      string
+	noReview        bool
 )
 
 var commitCmd = &cobra.Command{
@@ -40,105 +42,166 @@ Examples:
 			return
 		}
 
-		if cfg.AI.APIKey == "" {
+		if cfg.AI.APIKey == "" && cfg.AI.Provider.RequiresAPIKey() {
 			fmt.Println("Error: No API key configured. Please run 'commet config set' first.")
 			return
 		}
 
-		var gitDiff string
-
-		// Check if interactive mode should be used (flag or config)
-		shouldUseInteractive := interactiveMode || cfg.Git.Interactive
+		runCommit(cfg, newRepository())
+	},
+}
 
-		if shouldUseInteractive {
-			gitDiff, err = handleInteractiveFileSelection(cfg)
-		} else {
-			s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-			s.Suffix = " Analyzing git changes..."
-			s.Start()
-			gitDiff, err = git.GetDiff(cfg)
-			s.Stop()
-		}
+// runCommit collects the changes, produces a message and commits them
+func runCommit(cfg *config.Config, repo git.Repository) {
+	var (
+		gitDiff string
+		err     error
+	)
+
+	// Check if interactive mode should be used (flag or config)
+	shouldUseInteractive := interactiveMode || cfg.Git.Interactive
+
+	if shouldUseInteractive {
+		gitDiff, err = handleInteractiveFileSelection(repo)
+	} else {
+		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		s.Suffix = " Analyzing git changes..."
+		s.Start()
+		gitDiff, err = repo.GetDiff(cfg)
+		s.Stop()
+	}
 
-		if err != nil {
-			if err.Error() == "cancelled" {
-				// User cancelled file selection - exit silently
-				return
-			}
-			color.Red("Error getting git diff: %v\n", err)
+	if err != nil {
+		if err.Error() == "cancelled" {
+			// User cancelled file selection - exit silently
 			return
 		}
+		color.Red("Error getting git diff: %v\n", err)
+		return
+	}
 
-		if gitDiff == "" {
-			color.Yellow("No changes detected. Make sure you have staged changes or unstaged changes to commit.")
-			return
-		}
+	if gitDiff == "" {
+		color.Yellow("No changes detected. Make sure you have staged changes or unstaged changes to commit.")
+		return
+	}
 
-		color.Green("Found changes to commit")
+	color.Green("Found changes to commit")
 
-		var commitMsg string
+	if !passesReviewGate(cfg, repo, gitDiff) {
+		return
+	}
 
-		// Check if AI should be used
-		shouldUseAI := useAI || cfg.Git.UseAI
+	var commitMsg string
 
-		if shouldUseAI {
-			service, err := llm.NewService(cfg)
-			if err != nil {
-				fmt.Printf("Error creating LLM service: %v\n", err)
-				return
-			}
+	// Check if AI should be used
+	shouldUseAI := useAI || cfg.Git.UseAI
+
+	if shouldUseAI {
+		service, err := newGenerator(cfg, repo, "commit", noCache)
+		if err != nil {
+			fmt.Printf("Error creating LLM service: %v\n", err)
+			return
+		}
 
-			s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-			s.Suffix = fmt.Sprintf(" Generating commit message using %s...", cfg.AI.Provider)
-			s.Start()
+		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Generating commit message using %s...", cfg.AI.Provider)
+		s.Start()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+		defer cancel()
 
-			commitMsg, err = service.GenerateCommitMessage(ctx, gitDiff)
-			s.Stop()
+		commitMsg, err = service.GenerateCommitMessage(ctx, gitDiff)
+		s.Stop()
 
-			if err != nil {
-				color.Red("Error generating commit message: %v\n", err)
-				return
-			}
+		if err != nil {
+			color.Red("Error generating commit message: %v\n", err)
+			return
+		}
+		if service.FromCache() {
+			color.Cyan("Using cached commit message (run with --no-cache to regenerate)")
 		} else {
-			commitMsg, err = getManualCommitMessage()
-			if err != nil {
-				color.Red("Error getting commit message: %v\n", err)
-				return
-			}
+			color.Cyan("Usage: %s", service.Usage())
 		}
-
-		if shouldUseAI {
-			fmt.Printf("\n%s\n\n", commitMsg)
+	} else {
+		commitMsg, err = getManualCommitMessage()
+		if err != nil {
+			color.Red("Error getting commit message: %v\n", err)
+			return
 		}
+	}
 
-		clipboard.WriteAll(commitMsg)
+	if shouldUseAI {
+		fmt.Printf("\n%s\n\n", commitMsg)
+	}
 
-		shouldCommit := directCommit || cfg.Git.DirectCommit || !shouldUseAI
+	clipboard.WriteAll(commitMsg)
 
-		if shouldCommit {
-			if err := git.CreateCommit(commitMsg); err != nil {
-				color.Red("Error creating commit: %v\n", err)
-				return
-			}
-			color.Green("Commit created successfully!")
-		} else {
+	shouldCommit := directCommit || cfg.Git.DirectCommit || !shouldUseAI
+
+	if shouldCommit {
+		if err := repo.CreateCommit(commitMsg); err != nil {
+			color.Red("Error creating commit: %v\n", err)
 			return
 		}
+		color.Green("Commit created successfully!")
+	} else {
+		return
+	}
 
-		if cfg.Git.ConfirmPush {
-			if askForConfirmation("\nDo you want to push the changes?") {
-				if err := git.PushChanges(); err != nil {
-					color.Red("Error pushing changes: %v\n", err)
-					return
-				}
-				color.Green("Changes pushed successfully!")
+	if cfg.Git.ConfirmPush {
+		if askForConfirmation("\nDo you want to push the changes?") {
+			if err := repo.PushChanges(); err != nil {
+				color.Red("Error pushing changes: %v\n", err)
+				return
 			}
+			color.Green("Changes pushed successfully!")
 		}
+	}
+}
 
-	},
+// passesReviewGate reviews the changes when a review gate is configured and
+// reports whether the commit may go ahead
+func passesReviewGate(cfg *config.Config, repo git.Repository, gitDiff string) bool {
+	gate := cfg.Git.ReviewGate
+	if reviewGate != "" {
+		var err error
+		if gate, err = config.ParseSeverity(reviewGate); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return false
+		}
+	}
+	if gate == "" || noReview {
+		return true
+	}
+
+	service, err := newGenerator(cfg, repo, "review", false)
+	if err != nil {
+		fmt.Printf("Error creating LLM service: %v\n", err)
+		return false
+	}
+	findings, err := reviewChanges(cfg, service, gitDiff)
+	if err != nil {
+		color.Red("Error reviewing changes: %v\n", err)
+		return false
+	}
+
+	if review.Incomplete(findings) {
+		fmt.Println()
+		review.Write(os.Stdout, review.FormatText, findings)
+		color.Red("\nCommit aborted: the changes are too large to review in full (use --no-review to commit anyway)")
+		return false
+	}
+
+	blocking := review.AtLeast(findings, gate)
+	if len(blocking) == 0 {
+		color.Green("Review passed (%d finding(s) below %s)", len(findings), gate)
+		return true
+	}
+
+	fmt.Println()
+	review.Write(os.Stdout, review.FormatText, findings)
+	color.Red("\nCommit aborted: %d finding(s) at or above %s severity (use --no-review to commit anyway)", len(blocking), gate)
+	return false
 }
 
 func askForConfirmation(question string) bool {
@@ -153,43 +216,25 @@ func askForConfirmation(question string) bool {
 	return response == "y" || response == "yes"
 }
 
-func handleInteractiveFileSelection(cfg *config.Config) (string, error) {
-	unstagedFiles, err := git.GetUnstagedFiles()
+func handleInteractiveFileSelection(repo git.Repository) (string, error) {
+	entries, err := repo.Status()
 	if err != nil {
-		return "", fmt.Errorf("failed to get unstaged files: %w", err)
-	}
-
-	stagedFiles, err := git.GetStagedFiles()
-	if err != nil {
-		return "", fmt.Errorf("failed to get staged files: %w", err)
-	}
-
-	untrackedFiles, err := git.GetUntrackedFiles()
-	if err != nil {
-		return "", fmt.Errorf("failed to get untracked files: %w", err)
-	}
-
-	allFiles := make(map[string]bool)
-	for _, file := range unstagedFiles {
-		allFiles[file] = true
-	}
-	for _, file := range stagedFiles {
-		allFiles[file] = true
-	}
-	for _, file := range untrackedFiles {
-		allFiles[file] = true
+		return "", err
 	}
 
-	availableFiles := make([]string, 0, len(allFiles))
-	for file := range allFiles {
-		availableFiles = append(availableFiles, file)
+	// Selected files with unstaged or untracked changes need staging
+	needsStaging := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsUnstaged() || e.IsUntracked() {
+			needsStaging[e.Path] = true
+		}
 	}
 
-	if len(availableFiles) == 0 {
+	if len(entries) == 0 {
 		return "", fmt.Errorf("no files with changes found")
 	}
 
-	selectedFiles, filesToUnstage, err := ui.RunEnhancedFileSelector(availableFiles, stagedFiles, unstagedFiles, untrackedFiles)
+	selectedFiles, filesToUnstage, hunkPatches, err := selectFiles(repo, entries)
 	if err != nil {
 		if err.Error() == "user cancelled file selection" {
 			// User pressed 'q' to quit - exit gracefully without showing error
@@ -205,41 +250,37 @@ func handleInteractiveFileSelection(cfg *config.Config) (string, error) {
 	// Unstage files that were deselected
 	if len(filesToUnstage) > 0 {
 		color.Cyan("Unstaging deselected files: %v", filesToUnstage)
-		if err := git.UnstageFiles(filesToUnstage); err != nil {
+		if err := repo.UnstageFiles(filesToUnstage); err != nil {
 			return "", fmt.Errorf("failed to unstage files: %w", err)
 		}
 	}
 
 	var filesToStage []string
 	for _, file := range selectedFiles {
-		needsStaging := false
-		for _, unstaged := range unstagedFiles {
-			if file == unstaged {
-				needsStaging = true
-				break
-			}
+		if _, partial := hunkPatches[file]; partial {
+			// Only the chosen hunks are staged below
+			continue
 		}
-		if !needsStaging {
-			for _, untracked := range untrackedFiles {
-				if file == untracked {
-					needsStaging = true
-					break
-				}
-			}
-		}
-		if needsStaging {
+		if needsStaging[file] {
 			filesToStage = append(filesToStage, file)
 		}
 	}
 
 	if len(filesToStage) > 0 {
 		color.Cyan("Staging selected files: %v", filesToStage)
-		if err := git.StageFiles(filesToStage); err != nil {
+		if err := repo.StageFiles(filesToStage); err != nil {
 			return "", fmt.Errorf("failed to stage files: %w", err)
 		}
 	}
 
-	return git.GetDiffForFiles(selectedFiles, true)
+	for file, patch := range hunkPatches {
+		color.Cyan("Staging selected hunks of %s", file)
+		if err := repo.StagePatch(patch); err != nil {
+			return "", fmt.Errorf("failed to stage hunks of %s: %w", file, err)
+		}
+	}
+
+	return repo.GetDiffForFiles(selectedFiles, true)
 }
 
 func getManualCommitMessage() (string, error) {
@@ -264,5 +305,8 @@ func init() {
 	commitCmd.Flags().BoolVarP(&interactiveMode, "interactive", "i", false, "Interactive file selection mode")
 	commitCmd.Flags().BoolVarP(&directCommit, "yes", "y", false, "Commit directly without confirmation")
 	commitCmd.Flags().BoolVarP(&useAI, "ai", "a", false, "Use AI to generate commit message")
+	commitCmd.Flags().StringVar(&reviewGate, "review-gate", "", "Review changes with AI first and abort on findings at least this severe")
+	commitCmd.Flags().BoolVar(&noReview, "no-review", false, "Skip the review gate configured in git.review_gate")
+	commitCmd.Flags().BoolVar(&noCache, "no-cache", false, "Always generate a new message instead of reusing a cached one")
 	rootCmd.AddCommand(commitCmd)
 }
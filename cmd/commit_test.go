@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/llm"
+	"github.com/bitcs/commet/internal/review"
+	"github.com/bitcs/commet/internal/testutil"
+	"github.com/bitcs/commet/internal/usage"
+)
+
+const testMessage = "test: commit from the fake generator"
+
+// fakeGenerator answers commit message requests with a fixed message and
+// reviews with findings, and records the diffs it was asked about
+type fakeGenerator struct {
+	llm.Generator
+	diffs    []string
+	findings []review.Finding
+}
+
+func (g *fakeGenerator) GenerateCommitMessage(ctx context.Context, gitDiff string) (string, error) {
+	g.diffs = append(g.diffs, gitDiff)
+	return testMessage, nil
+}
+
+func (g *fakeGenerator) ReviewDiff(ctx context.Context, gitDiff string) ([]review.Finding, error) {
+	return g.findings, nil
+}
+
+func (g *fakeGenerator) FromCache() bool    { return false }
+func (g *fakeGenerator) Usage() usage.Usage { return usage.Usage{} }
+
+// selection is what the scripted file selector returns, along with the
+// status entries it was shown
+type selection struct {
+	selected []string
+	unstage  []string
+	patches  func(repo git.Repository) map[string]string
+	err      error
+	shown    []git.StatusEntry
+}
+
+// newTestRepo creates a repository with a.txt and b.txt committed and makes
+// it the current directory for the rest of the test
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := testutil.NewRepo(t)
+	testutil.WriteFile(t, "a.txt", "a\n")
+	testutil.WriteFile(t, "b.txt", "b\n")
+	testutil.Git(t, "add", ".")
+	testutil.Git(t, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+// useFakes runs runCommit non-interactively against the fake generator and
+// sel, restoring the real dependencies and flags afterwards
+func useFakes(t *testing.T, sel *selection) *fakeGenerator {
+	t.Helper()
+
+	gen := &fakeGenerator{}
+	origGenerator, origSelect := newGenerator, selectFiles
+	origFlags := []bool{interactiveMode, directCommit, useAI, noCache, noReview}
+	origGate := reviewGate
+	t.Cleanup(func() {
+		newGenerator, selectFiles = origGenerator, origSelect
+		interactiveMode, directCommit, useAI, noCache, noReview = origFlags[0], origFlags[1], origFlags[2], origFlags[3], origFlags[4]
+		reviewGate = origGate
+	})
+
+	newGenerator = func(*config.Config, git.Repository, string, bool) (llm.Generator, error) {
+		return gen, nil
+	}
+	selectFiles = func(repo git.Repository, entries []git.StatusEntry) ([]string, []string, map[string]string, error) {
+		if sel == nil {
+			t.Fatal("file selector used outside interactive mode")
+		}
+		sel.shown = entries
+		if sel.err != nil {
+			return nil, nil, nil, sel.err
+		}
+		var patches map[string]string
+		if sel.patches != nil {
+			patches = sel.patches(repo)
+		}
+		return sel.selected, sel.unstage, patches, nil
+	}
+
+	interactiveMode, directCommit, useAI, noCache, noReview = sel != nil, true, true, false, false
+	reviewGate = ""
+	return gen
+}
+
+func testConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.AI.Provider = config.ProviderFake
+	cfg.SetDefaults()
+	return cfg
+}
+
+// committedFiles returns the files changed by the HEAD commit
+func committedFiles(t *testing.T) []string {
+	t.Helper()
+	files := strings.Fields(testutil.Git(t, "show", "--name-only", "--format=", "HEAD"))
+	sort.Strings(files)
+	return files
+}
+
+func assertCommitted(t *testing.T, want ...string) {
+	t.Helper()
+	if got := testutil.Git(t, "log", "-1", "--format=%s"); got != testMessage {
+		t.Fatalf("HEAD subject = %q, want %q", got, testMessage)
+	}
+	if got := committedFiles(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("committed files = %v, want %v", got, want)
+	}
+}
+
+func assertNoCommit(t *testing.T) {
+	t.Helper()
+	if got := testutil.Git(t, "rev-list", "--count", "HEAD"); got != "1" {
+		t.Errorf("commit count = %s, want 1", got)
+	}
+}
+
+func TestRunCommitStagedChanges(t *testing.T) {
+	newTestRepo(t)
+	gen := useFakes(t, nil)
+
+	testutil.WriteFile(t, "a.txt", "a changed\n")
+	testutil.WriteFile(t, "b.txt", "b changed\n")
+	testutil.Git(t, "add", "a.txt")
+
+	runCommit(testConfig(), git.CLI{})
+
+	assertCommitted(t, "a.txt")
+	if len(gen.diffs) != 1 || !strings.Contains(gen.diffs[0], "a.txt") || strings.Contains(gen.diffs[0], "b.txt") {
+		t.Errorf("generator diffs = %q, want only the staged a.txt", gen.diffs)
+	}
+	if got := testutil.Git(t, "status", "--porcelain"); got != "M b.txt" {
+		t.Errorf("status after commit = %q, want b.txt still unstaged", got)
+	}
+}
+
+func TestRunCommitUnstagedChangesAreNotStaged(t *testing.T) {
+	newTestRepo(t)
+	gen := useFakes(t, nil)
+
+	testutil.WriteFile(t, "b.txt", "b changed\n")
+	testutil.WriteFile(t, "new.txt", "new\n")
+
+	runCommit(testConfig(), git.CLI{})
+
+	// Without auto-stage nothing is staged for the user, so git has nothing
+	// to commit
+	assertNoCommit(t)
+	if len(gen.diffs) != 1 || !strings.Contains(gen.diffs[0], "b.txt") {
+		t.Errorf("generator diffs = %q, want the working tree diff of b.txt", gen.diffs)
+	}
+	if got := testutil.Git(t, "status", "--porcelain"); got != "M b.txt\n?? new.txt" {
+		t.Errorf("status after commit = %q, want b.txt unstaged and new.txt untracked", got)
+	}
+}
+
+func TestRunCommitIgnoresUntrackedFiles(t *testing.T) {
+	newTestRepo(t)
+	gen := useFakes(t, nil)
+
+	testutil.WriteFile(t, "new.txt", "new\n")
+
+	runCommit(testConfig(), git.CLI{})
+
+	assertNoCommit(t)
+	if len(gen.diffs) != 0 {
+		t.Errorf("generator called with %q, want no call", gen.diffs)
+	}
+}
+
+func TestRunCommitAutoStage(t *testing.T) {
+	newTestRepo(t)
+	gen := useFakes(t, nil)
+
+	testutil.WriteFile(t, "a.txt", "a changed\n")
+	if err := os.Mkdir("sub", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	testutil.WriteFile(t, filepath.Join("sub", "new.txt"), "new\n")
+
+	cfg := testConfig()
+	cfg.Git.AutoStage = true
+	runCommit(cfg, git.CLI{})
+
+	assertCommitted(t, "a.txt", "sub/new.txt")
+	if len(gen.diffs) != 1 || !strings.Contains(gen.diffs[0], "sub/new.txt") {
+		t.Errorf("generator diffs = %q, want the auto-staged untracked file", gen.diffs)
+	}
+}
+
+func TestRunCommitAutoStageFromSubdirectory(t *testing.T) {
+	dir := newTestRepo(t)
+	useFakes(t, nil)
+
+	testutil.WriteFile(t, "a.txt", "a changed\n")
+	if err := os.Mkdir("sub", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	testutil.WriteFile(t, filepath.Join("sub", "new.txt"), "new\n")
+	if err := os.Chdir(filepath.Join(dir, "sub")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testConfig()
+	cfg.Git.AutoStage = true
+	runCommit(cfg, git.CLI{})
+
+	assertCommitted(t, "sub/new.txt")
+	if got := testutil.Git(t, "status", "--porcelain"); got != "M a.txt" {
+		t.Errorf("status after commit = %q, want a.txt outside sub left unstaged", got)
+	}
+}
+
+func TestRunCommitInteractiveSelection(t *testing.T) {
+	newTestRepo(t)
+	sel := &selection{selected: []string{"b.txt", "new.txt"}, unstage: []string{"a.txt"}}
+	gen := useFakes(t, sel)
+
+	testutil.WriteFile(t, "a.txt", "a changed\n")
+	testutil.WriteFile(t, "b.txt", "b changed\n")
+	testutil.WriteFile(t, "new.txt", "new\n")
+	testutil.Git(t, "add", "a.txt")
+
+	runCommit(testConfig(), git.CLI{})
+
+	var shown []string
+	for _, e := range sel.shown {
+		shown = append(shown, e.Code()+" "+e.Path)
+	}
+	if want := []string{"M  a.txt", " M b.txt", "?? new.txt"}; !reflect.DeepEqual(shown, want) {
+		t.Errorf("selector shown %q, want %q", shown, want)
+	}
+	assertCommitted(t, "b.txt", "new.txt")
+	if len(gen.diffs) != 1 || strings.Contains(gen.diffs[0], "a.txt") {
+		t.Errorf("generator diffs = %q, want only the selected files", gen.diffs)
+	}
+	if got := testutil.Git(t, "status", "--porcelain"); got != "M a.txt" {
+		t.Errorf("status after commit = %q, want a.txt unstaged", got)
+	}
+}
+
+func TestRunCommitInteractiveHunks(t *testing.T) {
+	newTestRepo(t)
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	testutil.WriteFile(t, "a.txt", strings.Join(lines, "\n")+"\n")
+	testutil.Git(t, "commit", "-q", "-am", "long file")
+
+	lines[0], lines[19] = "first changed", "last changed"
+	testutil.WriteFile(t, "a.txt", strings.Join(lines, "\n")+"\n")
+
+	sel := &selection{
+		selected: []string{"a.txt"},
+		patches: func(repo git.Repository) map[string]string {
+			diff, err := repo.GetFileDiff("a.txt", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fd, err := git.ParseFileDiff(diff)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(fd.Hunks) != 2 {
+				t.Fatalf("got %d hunks, want 2", len(fd.Hunks))
+			}
+			return map[string]string{"a.txt": fd.Patch([]int{0})}
+		},
+	}
+	useFakes(t, sel)
+
+	runCommit(testConfig(), git.CLI{})
+
+	committed := testutil.Git(t, "show", "HEAD:a.txt")
+	if !strings.HasPrefix(committed, "first changed\n") || strings.Contains(committed, "last changed") {
+		t.Errorf("committed a.txt = %q, want only the first hunk", committed)
+	}
+	if !strings.Contains(testutil.Git(t, "diff"), "+last changed") {
+		t.Error("second hunk should remain unstaged")
+	}
+}
+
+func TestRunCommitInteractiveCancelled(t *testing.T) {
+	newTestRepo(t)
+	gen := useFakes(t, &selection{err: errors.New("user cancelled file selection")})
+
+	testutil.WriteFile(t, "a.txt", "a changed\n")
+
+	runCommit(testConfig(), git.CLI{})
+
+	assertNoCommit(t)
+	if len(gen.diffs) != 0 {
+		t.Errorf("generator called with %q, want no call", gen.diffs)
+	}
+}
+
+func TestRunCommitReviewGate(t *testing.T) {
+	incomplete := review.Finding{Severity: config.SeverityHigh, Category: review.CategoryIncomplete, Message: "only part reviewed"}
+	tests := []struct {
+		name     string
+		findings []review.Finding
+		commit   bool
+	}{
+		{name: "no findings", commit: true},
+		{name: "below the gate", findings: []review.Finding{{File: "a.txt", Severity: config.SeverityHigh, Message: "m"}}, commit: true},
+		{name: "at the gate", findings: []review.Finding{{File: "a.txt", Severity: config.SeverityCritical, Message: "m"}}},
+		{name: "truncated diff", findings: []review.Finding{incomplete}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestRepo(t)
+			gen := useFakes(t, nil)
+			gen.findings = tt.findings
+			reviewGate = "critical"
+
+			testutil.WriteFile(t, "a.txt", "a changed\n")
+			testutil.Git(t, "add", "a.txt")
+
+			runCommit(testConfig(), git.CLI{})
+
+			if tt.commit {
+				assertCommitted(t, "a.txt")
+			} else {
+				assertNoCommit(t)
+			}
+		})
+	}
+}
@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/bitcs/commet/internal/config"
 	"github.com/bitcs/commet/internal/ui"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var configCmd = &cobra.Command{
@@ -26,6 +32,9 @@ var configShowCmd = &cobra.Command{
 		}
 
 		fmt.Println("Current Configuration:")
+		if cfg.ActiveProfile != "" {
+			fmt.Printf("  Profile: %s\n", cfg.ActiveProfile)
+		}
 		fmt.Printf("  AI Provider: %s\n", cfg.AI.Provider)
 		fmt.Printf("  API Key: %s\n", cfg.MaskAPIKey())
 
@@ -34,19 +43,88 @@ var configShowCmd = &cobra.Command{
 			model = cfg.GetDefaultModel()
 		}
 		fmt.Printf("  Model: %s\n", model)
-		
+		fmt.Printf("  Style: %s\n", cfg.AI.Style)
+		if cfg.AI.Temperature != nil {
+			fmt.Printf("  Temperature: %g\n", *cfg.AI.Temperature)
+		}
+		if cfg.AI.TopP != nil {
+			fmt.Printf("  Top P: %g\n", *cfg.AI.TopP)
+		}
+		if cfg.AI.MaxTokens > 0 {
+			fmt.Printf("  Max Tokens: %d\n", cfg.AI.MaxTokens)
+		}
+		fmt.Printf("  Timeout: %s\n", cfg.AI.RequestTimeout())
+		fmt.Printf("  Retries: %d\n", cfg.AI.MaxAttempts())
+		for i, f := range cfg.AI.Fallbacks {
+			fmt.Printf("  Fallback %d: %s\n", i+1, f)
+		}
+		if cfg.AI.SystemPrompt != "" {
+			fmt.Printf("  System Prompt: %s\n", cfg.AI.SystemPrompt)
+		}
+
 		fmt.Println("\nGit Settings:")
 		fmt.Printf("  Auto Stage: %t\n", cfg.Git.AutoStage)
 		fmt.Printf("  Show Diff: %t\n", cfg.Git.ShowDiff)
 		fmt.Printf("  Confirm Push: %t\n", cfg.Git.ConfirmPush)
+		fmt.Printf("  Direct Commit: %t\n", cfg.Git.DirectCommit)
+		fmt.Printf("  Use AI: %t\n", cfg.Git.UseAI)
+		fmt.Printf("  Interactive: %t\n", cfg.Git.Interactive)
+		if cfg.Git.ReviewGate != "" {
+			fmt.Printf("  Review Gate: %s\n", cfg.Git.ReviewGate)
+		}
+
+		if cfg.Forge.Type != "" || cfg.Forge.BaseURL != "" || cfg.Forge.Token != "" {
+			fmt.Println("\nForge Settings:")
+			if cfg.Forge.Type != "" {
+				fmt.Printf("  Type: %s\n", cfg.Forge.Type)
+			}
+			if cfg.Forge.BaseURL != "" {
+				fmt.Printf("  Base URL: %s\n", cfg.Forge.BaseURL)
+			}
+			if cfg.Forge.Token != "" {
+				fmt.Printf("  Token: %s\n", cfg.Forge.MaskToken())
+			}
+		}
+
+		if names := cfg.ProfileNames(); len(names) > 0 {
+			fmt.Println("\nProfiles:")
+			for _, name := range names {
+				marker := " "
+				if name == cfg.ActiveProfile {
+					marker = "*"
+				}
+				fmt.Printf(" %s %s\n", marker, name)
+			}
+		}
 	},
 }
 
 var configSetCmd = &cobra.Command{
-	Use:   "set",
+	Use:   "set [key] [value]",
 	Short: "Set configuration values",
-	Long:  `Set configuration values using command line flags or interactive mode.`,
+	Long: `Set configuration values using a key and value, command line flags or interactive mode.
+
+Examples:
+  commet config set git.auto_stage true
+  commet config set profiles.work.ai.provider claude
+  commet config set --provider openai --model gpt-4o
+  commet config set                         # Interactive mode`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("expected a key and a value, got %d argument(s)", len(args))
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 2 {
+			if err := config.SetValue(args[0], args[1]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Set %s\n", args[0])
+			return
+		}
+
 		cfg, err := config.Load()
 		if err != nil {
 			fmt.Printf("Error loading config: %v\n", err)
@@ -56,8 +134,9 @@ var configSetCmd = &cobra.Command{
 		provider, _ := cmd.Flags().GetString("provider")
 		apiKey, _ := cmd.Flags().GetString("api-key")
 		model, _ := cmd.Flags().GetString("model")
+		style, _ := cmd.Flags().GetString("style")
 
-		if provider == "" && apiKey == "" && model == "" {
+		if provider == "" && apiKey == "" && model == "" && style == "" {
 			if err := ui.RunConfigUI(cfg); err != nil {
 				fmt.Printf("Error: TUI not available (%v)\n", err)
 				return
@@ -71,17 +150,26 @@ var configSetCmd = &cobra.Command{
 				fmt.Printf("Error: %v\n", err)
 				return
 			}
-			cfg.AI.Provider = p
+			cfg.AI.SetProvider(p)
 		}
 
 		if apiKey != "" {
-			cfg.AI.APIKey = apiKey
+			cfg.AI.SetAPIKey(apiKey)
 		}
 
 		if model != "" {
 			cfg.AI.Model = model
 		}
 
+		if style != "" {
+			st, err := config.ParseStyle(style)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			cfg.AI.Style = st
+		}
+
 		if err := cfg.Save(); err != nil {
 			fmt.Printf("Error saving config: %v\n", err)
 			return
@@ -91,12 +179,169 @@ var configSetCmd = &cobra.Command{
 	},
 }
 
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long:  `Print the effective value of a configuration key, including profile overrides.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, err := config.LookupKey(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		if strings.HasPrefix(key.Name, "profiles.") {
+			value, _ := config.FileValue(key.Name)
+			fmt.Println(key.Format(value))
+			return
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+		fmt.Println(key.Format(key.Value(cfg)))
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Long:  `Remove a key from the config file so its default value applies again.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.UnsetValue(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Unset %s\n", args[0])
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all configuration keys",
+	Long:  `List every configuration key with its effective value and description.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, key := range config.Keys() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", key.Name, key.Format(key.Value(cfg)), key.Description)
+		}
+		w.Flush()
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in your editor",
+	Long:  `Open the config file in $VISUAL or $EDITOR and validate it after editing.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.FilePath()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			editor = "vi"
+		}
+
+		// Editors may carry arguments, e.g. "code --wait"
+		parts := strings.Fields(editor)
+		editCmd := exec.Command(parts[0], append(parts[1:], path)...)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		if err := editCmd.Run(); err != nil {
+			fmt.Printf("Error running editor: %v\n", err)
+			return
+		}
+
+		printValidation(path)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for errors",
+	Long:  `Check the config file for unknown keys and invalid values.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.FilePath()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		if !printValidation(path) {
+			os.Exit(1)
+		}
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show which config files are used",
+	Long:  `Show the config files commet reads, in order, and which one is loaded.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		loaded := viper.ConfigFileUsed()
+		for _, path := range configSearchPaths() {
+			status := "not found"
+			if path == loaded {
+				status = "loaded"
+			} else if _, err := os.Stat(path); err == nil {
+				status = "present"
+			}
+			fmt.Printf("%s (%s)\n", path, status)
+		}
+
+		cfg, err := config.Load()
+		if err == nil && cfg.ActiveProfile != "" {
+			fmt.Printf("\nActive profile: %s\n", cfg.ActiveProfile)
+		}
+	},
+}
+
+// printValidation reports validation errors for the config file and returns
+// whether it is valid
+func printValidation(path string) bool {
+	errs := config.Validate(path)
+	if len(errs) == 0 {
+		color.Green("%s is valid", path)
+		return true
+	}
+
+	color.Red("%s has %d problem(s):", path, len(errs))
+	for _, err := range errs {
+		fmt.Printf("  - %v\n", err)
+	}
+	return false
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configPathCmd)
 
 	configSetCmd.Flags().StringP("provider", "p", "", "AI provider (openai, claude, google, groq)")
 	configSetCmd.Flags().StringP("api-key", "k", "", "API key for the AI provider")
 	configSetCmd.Flags().StringP("model", "m", "", "AI model to use")
+	configSetCmd.Flags().StringP("style", "s", "", "Commit message style (conventional, simple, detailed)")
 }
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/llm"
+	"github.com/bitcs/commet/internal/ui"
+)
+
+// newRepository and newGenerator construct the dependencies of the commands
+// and selectFiles runs the interactive file picker. They are variables so
+// tests can swap in temporary repositories, fake generators and scripted
+// selections without touching the command handlers.
+var (
+	newRepository = func() git.Repository {
+		return git.CLI{}
+	}
+
+	newGenerator = func(cfg *config.Config, repo git.Repository, command string, noCache bool) (llm.Generator, error) {
+		service, err := llm.NewService(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if noCache {
+			service.DisableCache()
+		}
+		name, _ := repo.GetRepositoryName()
+		service.SetOrigin(command, name)
+		return service, nil
+	}
+
+	selectFiles = ui.RunEnhancedFileSelector
+)
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/prompts"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var explainDepth string
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [rev|range]",
+	Short: "Explain a commit or range of commits in plain language",
+	Long: `Explain what a commit or a range of commits changed and why, based on the
+commit messages and patches. Defaults to HEAD.
+
+Examples:
+  commet explain                       # Explain the last commit
+  commet explain a1b2c3d               # Explain a specific commit
+  commet explain main..feature         # Explain everything on a branch
+  commet explain v1.2.0..v1.3.0 --depth brief`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		if cfg.AI.APIKey == "" && cfg.AI.Provider.RequiresAPIKey() {
+			fmt.Println("Error: No API key configured. Please run 'commet config set' first.")
+			return
+		}
+
+		depth, err := prompts.ParseDepth(explainDepth)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		spec := "HEAD"
+		if len(args) == 1 {
+			spec = args[0]
+		}
+
+		history, err := git.Show(spec)
+		if err != nil {
+			color.Red("Error reading history: %v\n", err)
+			return
+		}
+		if history == "" {
+			color.Yellow("No commits in %s.", spec)
+			return
+		}
+
+		service, err := newGenerator(cfg, newRepository(), "explain", false)
+		if err != nil {
+			fmt.Printf("Error creating LLM service: %v\n", err)
+			return
+		}
+
+		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Explaining %s using %s...", spec, cfg.AI.Provider)
+		s.Start()
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+		defer cancel()
+
+		explanation, err := service.ExplainChanges(ctx, spec, history, depth)
+		s.Stop()
+		if err != nil {
+			color.Red("Error explaining changes: %v\n", err)
+			return
+		}
+		color.Cyan("Usage: %s", service.Usage())
+
+		fmt.Printf("\n%s\n", explanation)
+	},
+}
+
+func init() {
+	explainCmd.Flags().StringVar(&explainDepth, "depth", "normal", "Level of detail (brief, normal, detailed)")
+	rootCmd.AddCommand(explainCmd)
+}
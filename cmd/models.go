@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/llm"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Inspect the models offered by AI providers",
+}
+
+var modelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available models",
+	Long: `List the models offered by an AI provider, fetched from its API and cached
+for a day. The built-in list is shown when the provider cannot be reached.
+
+Examples:
+  commet models list                 # Models of the configured provider
+  commet models list -p claude       # Models of a specific provider
+  commet models list --refresh       # Ignore the cache`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		provider := cfg.AI.Provider
+		if name, _ := cmd.Flags().GetString("provider"); name != "" {
+			provider, err = config.ParseProvider(name)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+		refresh, _ := cmd.Flags().GetBool("refresh")
+
+		list, err := llm.ListModels(context.Background(), cfg, provider, refresh)
+		if err != nil {
+			color.Yellow("Could not fetch models from %s (%v); showing built-in list", provider, err)
+		}
+
+		current := cfg.AI.Model
+		if current == "" && provider == cfg.AI.Provider {
+			current = cfg.GetDefaultModel()
+		}
+		for _, model := range list.Models {
+			marker := " "
+			if model == current && provider == cfg.AI.Provider {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, model)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+	modelsCmd.AddCommand(modelsListCmd)
+
+	modelsListCmd.Flags().StringP("provider", "p", "", "AI provider (openai, claude, google, groq)")
+	modelsListCmd.Flags().Bool("refresh", false, "Fetch the list from the provider even if cached")
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/forge"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prBase   string
+	prOutput string
+	prCopy   bool
+	prCreate bool
+	prDraft  bool
+	prRemote string
+)
+
+// prTemplatePaths are the locations GitHub reads pull request templates from
+var prTemplatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+}
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Generate a pull request title and description",
+	Long: `Generate a pull request title and Markdown description from the commits and
+diff of the current branch since it diverged from the base branch. A pull
+request template in the repository (.github/pull_request_template.md and the
+other locations GitHub supports) is followed when present.
+
+Examples:
+  commet pr                        # Print the title and description
+  commet pr --base origin/develop  # Compare against another base
+  commet pr -c                     # Also copy to the clipboard
+  commet pr -o pr.md               # Write to a file
+  commet pr --create               # Push and open or update the pull request
+
+With --create the branch is pushed and the pull request (GitHub) or merge
+request (GitLab) is opened, or updated when one is already open. The forge is
+detected from the remote URL; set forge.base_url for GitHub Enterprise or
+self-hosted GitLab. The token comes from forge.token, GH_TOKEN/GITHUB_TOKEN
+or GITLAB_TOKEN/GLAB_TOKEN.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		if cfg.AI.APIKey == "" && cfg.AI.Provider.RequiresAPIKey() {
+			fmt.Println("Error: No API key configured. Please run 'commet config set' first.")
+			return
+		}
+
+		base := prBase
+		if base == "" {
+			if base, err = git.DefaultBaseBranch(prRemote); err != nil {
+				color.Red("Error: %v (use --base)\n", err)
+				return
+			}
+		}
+
+		mergeBase, err := git.MergeBase(base, "HEAD")
+		if err != nil {
+			color.Red("Error finding merge base: %v\n", err)
+			return
+		}
+
+		commits, err := git.GetCommits(mergeBase, "HEAD")
+		if err != nil {
+			color.Red("Error reading commits: %v\n", err)
+			return
+		}
+		if len(commits) == 0 {
+			color.Yellow("No commits on this branch since %s.", base)
+			return
+		}
+
+		gitDiff, err := git.GetRangeDiff(mergeBase, "HEAD")
+		if err != nil {
+			color.Red("Error getting diff: %v\n", err)
+			return
+		}
+
+		var messages []string
+		for _, c := range commits {
+			messages = append(messages, "- "+strings.ReplaceAll(c.Message(), "\n", "\n  "))
+		}
+
+		service, err := newGenerator(cfg, newRepository(), "pr", false)
+		if err != nil {
+			fmt.Printf("Error creating LLM service: %v\n", err)
+			return
+		}
+
+		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Writing pull request for %d commit(s) against %s...", len(commits), base)
+		s.Start()
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+		defer cancel()
+
+		pr, err := service.GeneratePullRequest(ctx, strings.Join(messages, "\n"), gitDiff, readPRTemplate())
+		s.Stop()
+		if err != nil {
+			color.Red("Error generating pull request: %v\n", err)
+			return
+		}
+		color.Cyan("Usage: %s", service.Usage())
+
+		text := pr.String()
+		if prOutput != "" {
+			if err := os.WriteFile(prOutput, []byte(text+"\n"), 0644); err != nil {
+				color.Red("Error writing %s: %v\n", prOutput, err)
+				return
+			}
+			color.Green("Pull request written to %s", prOutput)
+		} else {
+			fmt.Printf("\n%s\n\n", text)
+		}
+
+		if prCopy {
+			if err := clipboard.WriteAll(text); err != nil {
+				color.Red("Error copying to clipboard: %v\n", err)
+				return
+			}
+			color.Green("Copied to clipboard")
+		}
+
+		if prCreate {
+			if err := publishPullRequest(cfg, base, pr.Title, pr.Body); err != nil {
+				color.Red("Error publishing pull request: %v\n", err)
+			}
+		}
+	},
+}
+
+// publishPullRequest pushes the current branch and opens a pull request into
+// base, or updates the one that is already open
+func publishPullRequest(cfg *config.Config, base, title, body string) error {
+	branch, err := git.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+	if branch == "" {
+		return fmt.Errorf("HEAD is detached; check out a branch first")
+	}
+
+	remoteURL, err := git.GetRemoteURL(prRemote)
+	if err != nil {
+		return err
+	}
+	repo, err := forge.ParseRemote(remoteURL)
+	if err != nil {
+		return err
+	}
+	client, err := forge.New(cfg.Forge, repo, nil)
+	if err != nil {
+		return err
+	}
+
+	// Push to the remote the pull request is opened on, keeping an existing
+	// upstream on another remote
+	if err := git.PushBranch(prRemote, branch, !git.HasUpstream()); err != nil {
+		return err
+	}
+	color.Green("Pushed %s to %s", branch, prRemote)
+
+	// The API wants the bare branch name, not the remote-tracking ref
+	base = strings.TrimPrefix(base, prRemote+"/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	existing, err := client.FindPullRequest(ctx, branch, base)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		updated, err := client.UpdatePullRequest(ctx, existing.Number, title, body)
+		if err != nil {
+			return err
+		}
+		color.Green("Updated pull request #%d: %s", updated.Number, updated.URL)
+		return nil
+	}
+
+	created, err := client.CreatePullRequest(ctx, branch, base, title, body, prDraft)
+	if err != nil {
+		return err
+	}
+	color.Green("Opened pull request #%d: %s", created.Number, created.URL)
+	return nil
+}
+
+// readPRTemplate returns the repository's pull request template, if any
+func readPRTemplate() string {
+	top, err := git.TopLevel()
+	if err != nil {
+		return ""
+	}
+	for _, path := range prTemplatePaths {
+		if data, err := os.ReadFile(filepath.Join(top, path)); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return ""
+}
+
+func init() {
+	prCmd.Flags().StringVarP(&prBase, "base", "b", "", "Base branch to compare against (default: the remote's HEAD, main or master)")
+	prCmd.Flags().StringVarP(&prOutput, "output", "o", "", "Write the title and description to a file instead of stdout")
+	prCmd.Flags().BoolVarP(&prCopy, "copy", "c", false, "Copy the title and description to the clipboard")
+	prCmd.Flags().BoolVar(&prCreate, "create", false, "Push the branch and open or update the pull request")
+	prCmd.Flags().BoolVar(&prDraft, "draft", false, "Open the pull request as a draft (with --create)")
+	prCmd.Flags().StringVar(&prRemote, "remote", "origin", "Remote to push to and open the pull request on")
+	rootCmd.AddCommand(prCmd)
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/changelog"
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/llm"
+	"github.com/bitcs/commet/internal/release"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseBump   string
+	releaseNoAI   bool
+	releaseDryRun bool
+	releaseYes    bool
+	releasePush   bool
+	releaseRemote string
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Tag the next semantic version with AI-written release notes",
+	Long: `Inspect the commits since the latest semantic version tag, work out the
+required bump (major for breaking changes, minor for features, patch for
+fixes) and create an annotated tag carrying release notes. Pushing the tag
+starts the .goreleaser.yaml workflow of projects released with GoReleaser.
+
+Examples:
+  commet release --dry-run         # Show the next version and notes
+  commet release                   # Create the tag after confirmation
+  commet release --bump major -y   # Force a bump without asking
+  commet release --push            # Create and push the tag`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		var forced release.Bump
+		if releaseBump != "" {
+			if forced, err = release.ParseBump(releaseBump); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		tags, err := git.GetTags("HEAD")
+		if err != nil {
+			color.Red("Error reading tags: %v\n", err)
+			return
+		}
+		current, from, found := release.Latest(tags)
+		if !found {
+			// The first release starts from 0.0.0 and covers the whole history
+			current = release.Version{Prefix: "v"}
+		}
+
+		commits, err := git.GetCommits(from, "HEAD")
+		if err != nil {
+			color.Red("Error reading commits: %v\n", err)
+			return
+		}
+		if len(commits) == 0 {
+			color.Yellow("No commits since %s.", from)
+			return
+		}
+
+		var service llm.Generator
+		if !releaseNoAI && (cfg.AI.APIKey != "" || !cfg.AI.Provider.RequiresAPIKey()) {
+			service, err = newGenerator(cfg, newRepository(), "release", false)
+			if err != nil {
+				fmt.Printf("Error creating LLM service: %v\n", err)
+				return
+			}
+		}
+
+		// Ambiguous, non-conventional commits are classified by the model.
+		// Individual commits rather than pull request titles decide the bump,
+		// since a breaking change is marked on the commit that makes it.
+		entries := changelogEntries(cfg, commits, service, false)
+
+		bump := release.Required(entries)
+		if forced != release.None {
+			bump = forced
+		}
+		if bump == release.None {
+			color.Yellow("No releasable changes in %d commit(s); use --bump to release anyway.", len(commits))
+			return
+		}
+		next := current.Apply(bump)
+
+		previous := "none"
+		if found {
+			previous = from
+		}
+		color.Cyan("Current version: %s", previous)
+		color.Green("Next version:    %s (%s)", next, bump)
+
+		notes, err := releaseNotes(cfg, service, next.String(), entries, commits)
+		if err != nil {
+			color.Red("Error generating release notes: %v\n", err)
+			return
+		}
+		if service != nil && service.Usage().Total() > 0 {
+			color.Cyan("Usage: %s", service.Usage())
+		}
+		fmt.Printf("\n%s\n\n", notes)
+
+		if releaseDryRun {
+			return
+		}
+		if !releaseYes && !askForConfirmation(fmt.Sprintf("Create tag %s?", next)) {
+			return
+		}
+
+		if err := git.CreateTag(next.String(), next.String()+"\n\n"+notes+"\n"); err != nil {
+			color.Red("Error creating tag: %v\n", err)
+			return
+		}
+		color.Green("Created tag %s", next)
+
+		if !releasePush {
+			color.Cyan("Push it with 'git push %s %s' or run with --push", releaseRemote, next)
+			return
+		}
+		if err := git.PushTag(releaseRemote, next.String()); err != nil {
+			color.Red("Error pushing tag: %v\n", err)
+			return
+		}
+		color.Green("Pushed tag %s to %s", next, releaseRemote)
+	},
+}
+
+// releaseNotes asks the model for release notes, or lists the changelog
+// entries when AI is not available
+func releaseNotes(cfg *config.Config, service llm.Generator, version string, entries []changelog.Entry, commits []git.Commit) (string, error) {
+	_, changes, _ := strings.Cut(changelog.Render(version, time.Now(), entries), "\n")
+	changes = strings.TrimSpace(changes)
+	if service == nil {
+		return changes, nil
+	}
+
+	var messages strings.Builder
+	for _, c := range commits {
+		fmt.Fprintf(&messages, "- %s\n", strings.ReplaceAll(c.Message(), "\n", "\n  "))
+	}
+
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	s.Suffix = fmt.Sprintf(" Writing release notes using %s...", cfg.AI.Provider)
+	s.Start()
+	defer s.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+	defer cancel()
+
+	return service.GenerateReleaseNotes(ctx, version, changes, messages.String())
+}
+
+func init() {
+	releaseCmd.Flags().StringVar(&releaseBump, "bump", "", "Force the bump instead of deriving it (major, minor or patch)")
+	releaseCmd.Flags().BoolVar(&releaseNoAI, "no-ai", false, "Do not use AI for classification and release notes")
+	releaseCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Show the next version and notes without tagging")
+	releaseCmd.Flags().BoolVarP(&releaseYes, "yes", "y", false, "Create the tag without confirmation")
+	releaseCmd.Flags().BoolVar(&releasePush, "push", false, "Push the tag after creating it")
+	releaseCmd.Flags().StringVar(&releaseRemote, "remote", "origin", "Remote to push the tag to")
+	rootCmd.AddCommand(releaseCmd)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/llm"
+	"github.com/bitcs/commet/internal/review"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewInteractive bool
+	reviewFormat      string
+	reviewOutput      string
+	reviewFailOn      string
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review staged changes with AI",
+	Long: `Send the staged changes to the AI for a code review and print its findings
+with their file, line, severity and message. JSON and SARIF output can be fed
+to other tools, for example GitHub code scanning.
+
+Examples:
+  commet review                       # Review staged (or unstaged) changes
+  commet review -i                    # Choose the files to review
+  commet review --format sarif -o review.sarif
+  commet review --fail-on high        # Exit with status 1 on high or critical findings`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		if cfg.AI.APIKey == "" && cfg.AI.Provider.RequiresAPIKey() {
+			fmt.Println("Error: No API key configured. Please run 'commet config set' first.")
+			return
+		}
+
+		format, err := review.ParseFormat(reviewFormat)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		var failOn config.Severity
+		if reviewFailOn != "" {
+			if failOn, err = config.ParseSeverity(reviewFailOn); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		repo := newRepository()
+		gitDiff, err := changesToReview(repo)
+		if err != nil {
+			if err.Error() == "cancelled" {
+				return
+			}
+			color.Red("Error getting git diff: %v\n", err)
+			return
+		}
+		if gitDiff == "" {
+			color.Yellow("No changes detected. Stage the changes you want reviewed.")
+			return
+		}
+
+		service, err := newGenerator(cfg, repo, "review", false)
+		if err != nil {
+			fmt.Printf("Error creating LLM service: %v\n", err)
+			return
+		}
+
+		findings, err := reviewChanges(cfg, service, gitDiff)
+		if err != nil {
+			color.Red("Error reviewing changes: %v\n", err)
+			return
+		}
+		// Keep stdout parseable for the machine-readable formats
+		color.New(color.FgCyan).Fprintf(os.Stderr, "Usage: %s\n", service.Usage())
+
+		var w io.Writer = os.Stdout
+		if reviewOutput != "" {
+			f, err := os.Create(reviewOutput)
+			if err != nil {
+				color.Red("Error creating output file: %v\n", err)
+				return
+			}
+			defer f.Close()
+			color.NoColor = true
+			w = f
+		}
+		if err := review.Write(w, format, findings); err != nil {
+			color.Red("Error writing findings: %v\n", err)
+			return
+		}
+		if reviewOutput != "" {
+			fmt.Fprintf(os.Stderr, "Wrote %d finding(s) to %s\n", len(findings), reviewOutput)
+		}
+
+		if failOn != "" && len(review.AtLeast(findings, failOn)) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// changesToReview returns the diff to review. Reviewing only reads the
+// repository, so git.auto_stage is not applied.
+func changesToReview(repo git.Repository) (string, error) {
+	if reviewInteractive {
+		return handleInteractiveFileSelection(repo)
+	}
+	return repo.GetPendingDiff()
+}
+
+// reviewChanges runs the AI review of gitDiff behind a spinner
+func reviewChanges(cfg *config.Config, service llm.Generator, gitDiff string) ([]review.Finding, error) {
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
+	s.Suffix = fmt.Sprintf(" Reviewing changes using %s...", cfg.AI.Provider)
+	s.Start()
+	defer s.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+	defer cancel()
+
+	return service.ReviewDiff(ctx, gitDiff)
+}
+
+func init() {
+	reviewCmd.Flags().BoolVarP(&reviewInteractive, "interactive", "i", false, "Interactive file selection mode")
+	reviewCmd.Flags().StringVarP(&reviewFormat, "format", "f", "text", "Output format (text, json, sarif)")
+	reviewCmd.Flags().StringVarP(&reviewOutput, "output", "o", "", "Write the findings to a file instead of stdout")
+	reviewCmd.Flags().StringVar(&reviewFailOn, "fail-on", "", "Exit with status 1 on findings at least this severe (info, low, medium, high, critical)")
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/testutil"
+)
+
+func TestChangesToReviewDoesNotStage(t *testing.T) {
+	newTestRepo(t)
+	reviewInteractive = false
+
+	testutil.WriteFile(t, "a.txt", "a changed\n")
+	testutil.WriteFile(t, "new.txt", "new\n")
+
+	diff, err := changesToReview(git.CLI{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+a changed") {
+		t.Errorf("diff = %q, want the unstaged change", diff)
+	}
+	if got := testutil.Git(t, "status", "--porcelain"); got != "M a.txt\n?? new.txt" {
+		t.Errorf("status = %q, want nothing staged", got)
+	}
+
+	testutil.Git(t, "add", "new.txt")
+	if diff, err = changesToReview(git.CLI{}); err != nil || !strings.Contains(diff, "+new") || strings.Contains(diff, "a changed") {
+		t.Errorf("diff = %q, %v, want only the staged new.txt", diff, err)
+	}
+}
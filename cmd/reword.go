@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/changelog"
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/reword"
+	"github.com/bitcs/commet/internal/ui"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rewordAll    bool
+	rewordYes    bool
+	rewordDryRun bool
+	rewordForce  bool
+)
+
+var rewordCmd = &cobra.Command{
+	Use:   "reword [base|base..HEAD]",
+	Short: "Rewrite poor commit messages on the current branch",
+	Long: `Find commits with messages such as "wip" or "fix" between base and HEAD,
+generate better messages from each commit's diff and, after you approve them,
+rewrite the branch like a rebase that only rewords. Trees, authors and the
+working tree are left untouched. The base defaults to the branch's merge base
+with the default branch. Commits that are already pushed are refused unless
+--force is given. fixup!, squash! and amend! commits are skipped so that
+git rebase --autosquash can still fold them in.
+
+Examples:
+  commet reword                    # Commits since branching off main
+  commet reword HEAD~5             # The last five commits
+  commet reword --all origin/main  # Regenerate every message on the branch
+  commet reword --dry-run          # Only print the proposals`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		if cfg.AI.APIKey == "" && cfg.AI.Provider.RequiresAPIKey() {
+			fmt.Println("Error: No API key configured. Please run 'commet config set' first.")
+			return
+		}
+
+		from, err := rangeBase(args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		// Refuse merges before spending any requests on the messages
+		merge, err := git.FindMerge(from)
+		if err != nil {
+			color.Red("Error reading commits: %v\n", err)
+			return
+		}
+		if merge != "" {
+			color.Red("Commit %s is a merge; only linear history can be reworded.", merge[:7])
+			return
+		}
+
+		commits, err := git.GetCommits(from, "HEAD")
+		if err != nil {
+			color.Red("Error reading commits: %v\n", err)
+			return
+		}
+
+		var candidates []reword.Candidate
+		for _, c := range commits {
+			if changelog.IsMerge(c) || changelog.IsPullRequestMerge(c) {
+				continue
+			}
+			if reword.IsAutosquash(c.Subject) {
+				color.Yellow("Skipping %s (%s); it is left for git rebase --autosquash.", c.Hash[:7], c.Subject)
+				continue
+			}
+			reason := reword.Assess(c.Message())
+			if reason == "" && rewordAll {
+				reason = "requested with --all"
+			}
+			if reason != "" {
+				candidates = append(candidates, reword.Candidate{Commit: c, Reason: reason, Approved: true})
+			}
+		}
+		if len(candidates) == 0 {
+			color.Green("All %d commit message(s) look fine.", len(commits))
+			return
+		}
+
+		if !rewordForce {
+			for _, c := range candidates {
+				pushed, err := git.IsPushed(c.Commit.Hash)
+				if err != nil {
+					color.Red("Error: %v\n", err)
+					return
+				}
+				if pushed {
+					color.Red("Commit %s (%s) is already pushed; rewording it rewrites published history. Use --force to reword anyway.", c.Commit.Hash[:7], c.Commit.Subject)
+					return
+				}
+			}
+		}
+
+		candidates, err = proposeMessages(cfg, candidates)
+		if err != nil {
+			color.Red("Error generating commit messages: %v\n", err)
+			return
+		}
+		if len(candidates) == 0 {
+			color.Yellow("None of the flagged commits have changes to describe.")
+			return
+		}
+
+		if rewordDryRun {
+			for _, c := range candidates {
+				color.Yellow("%s %s  (%s)", c.Commit.Hash[:7], c.Commit.Subject, c.Reason)
+				fmt.Printf("%s\n\n", c.Message)
+			}
+			return
+		}
+
+		if !rewordYes {
+			candidates, err = ui.RunRewordReview(candidates)
+			if err != nil {
+				if err.Error() == "user cancelled reword" {
+					return
+				}
+				color.Red("Error showing proposals: %v\n", err)
+				return
+			}
+		}
+
+		messages := map[string]string{}
+		for _, c := range candidates {
+			if c.Approved {
+				messages[c.Commit.Hash] = c.Message + "\n"
+			}
+		}
+		if len(messages) == 0 {
+			color.Yellow("No messages approved; history left unchanged.")
+			return
+		}
+
+		count, err := git.RewriteMessages(from, messages)
+		if err != nil {
+			color.Red("Error rewriting history: %v\n", err)
+			return
+		}
+		color.Green("Reworded %d commit(s)", count)
+		if rewordForce {
+			color.Cyan("If the branch was pushed, update it with 'git push --force-with-lease'")
+		}
+	},
+}
+
+// rangeBase returns the commit a branch range starts after: the merge base of
+// the given revision and HEAD, so a base that moved on since the branch was
+// created never pulls its newer commits into the range. A range must end at
+// HEAD, because only the current branch is rewritten.
+func rangeBase(args []string) (string, error) {
+	if len(args) == 0 {
+		base, err := git.DefaultBaseBranch("origin")
+		if err != nil {
+			return "", err
+		}
+		return git.MergeBase(base, "HEAD")
+	}
+
+	from, to, err := splitRange(args[0])
+	if err != nil {
+		return "", err
+	}
+	if to != "HEAD" {
+		target, err := git.RevParse(to)
+		if err != nil {
+			return "", err
+		}
+		head, err := git.RevParse("HEAD")
+		if err != nil {
+			return "", err
+		}
+		if target != head {
+			return "", fmt.Errorf("only the current branch can be reworded; the range must end at HEAD")
+		}
+	}
+	if _, err := git.RevParse(from); err != nil {
+		return "", err
+	}
+	return git.MergeBase(from, "HEAD")
+}
+
+// proposeMessages generates a replacement message for each candidate from its
+// diff, dropping commits without changes
+func proposeMessages(cfg *config.Config, candidates []reword.Candidate) ([]reword.Candidate, error) {
+	service, err := newGenerator(cfg, newRepository(), "reword", false)
+	if err != nil {
+		return nil, err
+	}
+
+	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	s.Start()
+	defer s.Stop()
+
+	var result []reword.Candidate
+	for i, c := range candidates {
+		s.Suffix = fmt.Sprintf(" Rewording commit %d/%d using %s...", i+1, len(candidates), cfg.AI.Provider)
+
+		diff, err := git.GetCommitDiff(c.Commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(diff) == "" {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+		c.Message, err = service.GenerateCommitMessage(ctx, diff)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Commit.Hash[:7], err)
+		}
+		result = append(result, c)
+	}
+
+	s.Stop()
+	color.Cyan("Usage: %s", service.Usage())
+	return result, nil
+}
+
+func init() {
+	rewordCmd.Flags().BoolVar(&rewordAll, "all", false, "Regenerate every message in the range, not only poor ones")
+	rewordCmd.Flags().BoolVarP(&rewordYes, "yes", "y", false, "Rewrite with the proposed messages without review")
+	rewordCmd.Flags().BoolVar(&rewordDryRun, "dry-run", false, "Print the proposed messages without rewriting")
+	rewordCmd.Flags().BoolVar(&rewordForce, "force", false, "Allow rewording commits that are already pushed")
+	rootCmd.AddCommand(rewordCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/bitcs/commet/internal/testutil"
+)
+
+func TestRangeBase(t *testing.T) {
+	newTestRepo(t)
+	fork := testutil.Git(t, "rev-parse", "HEAD")
+	base := testutil.Git(t, "symbolic-ref", "--short", "HEAD")
+
+	testutil.Git(t, "checkout", "-q", "-b", "feature")
+	testutil.WriteFile(t, "a.txt", "feature\n")
+	testutil.Git(t, "commit", "-q", "-am", "feat: change a")
+	testutil.WriteFile(t, "a.txt", "feature 2\n")
+	testutil.Git(t, "commit", "-q", "-am", "feat: change a again")
+	parent := testutil.Git(t, "rev-parse", "HEAD~1")
+
+	// The base branch moves on after the feature branch left it
+	testutil.Git(t, "checkout", "-q", base)
+	testutil.WriteFile(t, "b.txt", "upstream\n")
+	testutil.Git(t, "commit", "-q", "-am", "fix: upstream change")
+	testutil.Git(t, "checkout", "-q", "feature")
+
+	tests := map[string]string{
+		base:              fork,
+		base + "..HEAD":   fork,
+		"HEAD~1":          parent,
+		"HEAD~1..feature": parent,
+	}
+	for arg, want := range tests {
+		got, err := rangeBase([]string{arg})
+		if err != nil || got != want {
+			t.Errorf("rangeBase(%q) = %s, %v, want %s", arg, got, err, want)
+		}
+	}
+
+	for _, arg := range []string{"HEAD~2.." + base, base + "...HEAD", "no-such-rev"} {
+		if got, err := rangeBase([]string{arg}); err == nil {
+			t.Errorf("rangeBase(%q) = %s, want error", arg, got)
+		}
+	}
+}
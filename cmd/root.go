@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/bitcs/commet/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile     string
+	profileName string
+	workDir     string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "commet",
@@ -27,24 +32,46 @@ func Execute() {
 
 func init() {
 	cobra.OnInitialize(initConfig)
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.commet.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/commet/config.yaml, falling back to $HOME/.commet.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "configuration profile to use (overrides COMMET_PROFILE)")
+	rootCmd.PersistentFlags().StringVarP(&workDir, "directory", "C", "", "run as if commet was started in `path` (like git -C)")
 }
 
 func initConfig() {
+	// Change directory first so profile matching and git both see the
+	// requested repository
+	if workDir != "" {
+		if err := os.Chdir(workDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cannot change to %s: %v\n", workDir, err)
+			os.Exit(1)
+		}
+	}
+
+	viper.SetConfigType("yaml")
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 	} else {
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".commet")
+		for _, path := range config.SearchPaths() {
+			if _, err := os.Stat(path); err == nil {
+				viper.SetConfigFile(path)
+				break
+			}
+		}
 	}
 
 	viper.AutomaticEnv()
+	config.SelectProfile(profileName)
 
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
+
+// configSearchPaths lists the config files considered by initConfig in
+// lookup order
+func configSearchPaths() []string {
+	if cfgFile != "" {
+		return []string{cfgFile}
+	}
+	return config.SearchPaths()
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/split"
+	"github.com/bitcs/commet/internal/ui"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitYes    bool
+	splitDryRun bool
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split pending changes into several AI-planned commits",
+	Long: `Send all staged, unstaged and untracked changes to the AI, which groups
+files and hunks into a series of logical commits with messages. The plan is
+shown for reordering, editing and skipping before each group is staged and
+committed in turn. Skipped groups stay in the working tree.
+
+Examples:
+  commet split                     # Review the plan, then commit
+  commet split --dry-run           # Only print the proposed commits
+  commet split -y                  # Commit the plan as proposed`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		if cfg.AI.APIKey == "" && cfg.AI.Provider.RequiresAPIKey() {
+			fmt.Println("Error: No API key configured. Please run 'commet config set' first.")
+			return
+		}
+
+		changes, err := split.Collect()
+		if err != nil {
+			color.Red("Error collecting changes: %v\n", err)
+			return
+		}
+		if len(changes) == 0 {
+			color.Yellow("No changes detected.")
+			return
+		}
+
+		service, err := newGenerator(cfg, newRepository(), "split", false)
+		if err != nil {
+			fmt.Printf("Error creating LLM service: %v\n", err)
+			return
+		}
+
+		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Planning commits for %d change(s) using %s...", len(changes), cfg.AI.Provider)
+		s.Start()
+
+		// Plans cover the whole working tree, so allow more than one request's time
+		ctx, cancel := context.WithTimeout(context.Background(), 2*cfg.AI.RequestTimeout())
+		defer cancel()
+
+		planned, err := service.GenerateSplitPlan(ctx, split.Describe(changes))
+		s.Stop()
+		if err != nil {
+			color.Red("Error generating split plan: %v\n", err)
+			return
+		}
+		color.Cyan("Usage: %s", service.Usage())
+
+		messages := make([]string, len(planned))
+		ids := make([][]string, len(planned))
+		for i, p := range planned {
+			messages[i] = p.Message
+			ids[i] = p.Changes
+		}
+		groups := split.Arrange(changes, messages, ids, cfg.AI.Style)
+
+		if splitDryRun {
+			printSplitPlan(groups)
+			return
+		}
+
+		if !splitYes {
+			groups, err = ui.RunSplitPlanner(groups)
+			if err != nil {
+				if err.Error() == "user cancelled split" {
+					return
+				}
+				color.Red("Error showing split plan: %v\n", err)
+				return
+			}
+		}
+
+		created, err := split.Apply(groups)
+		if err != nil {
+			color.Red("Error splitting changes: %v\n", err)
+			if created == 0 {
+				color.Yellow("No commits were made and your staged changes were restored")
+			}
+			return
+		}
+		color.Green("Created %d commit(s)", created)
+	},
+}
+
+func printSplitPlan(groups []split.Group) {
+	for i, g := range groups {
+		subject, body, _ := strings.Cut(g.Message, "\n")
+		color.Cyan("%d. %s", i+1, subject)
+		if body = strings.TrimSpace(body); body != "" {
+			fmt.Printf("\n%s\n\n", body)
+		}
+		for _, c := range g.Changes {
+			fmt.Printf("   %s\n", c.Label())
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	splitCmd.Flags().BoolVarP(&splitYes, "yes", "y", false, "Commit the proposed plan without review")
+	splitCmd.Flags().BoolVar(&splitDryRun, "dry-run", false, "Print the proposed commits without committing")
+	rootCmd.AddCommand(splitCmd)
+}
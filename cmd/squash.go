@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	squashYes    bool
+	squashDryRun bool
+	squashForce  bool
+)
+
+var squashCmd = &cobra.Command{
+	Use:   "squash [base]",
+	Short: "Squash the commits since base into one with a synthesized message",
+	Long: `Combine the commits between base and HEAD into a single commit. The message
+is written by the AI from the combined diff and the individual messages,
+instead of concatenating them. The base defaults to the branch's merge base
+with the default branch. Commits that are already pushed are refused unless
+--force is given.
+
+Examples:
+  commet squash                    # Squash everything since branching off main
+  commet squash HEAD~3             # Squash the last three commits
+  commet squash --dry-run          # Only print the message`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			return
+		}
+
+		if cfg.AI.APIKey == "" && cfg.AI.Provider.RequiresAPIKey() {
+			fmt.Println("Error: No API key configured. Please run 'commet config set' first.")
+			return
+		}
+
+		base, err := rangeBase(args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		commits, err := git.GetCommits(base, "HEAD")
+		if err != nil {
+			color.Red("Error reading commits: %v\n", err)
+			return
+		}
+		if len(commits) < 2 {
+			color.Yellow("Nothing to squash: %d commit(s) since %s.", len(commits), base[:7])
+			return
+		}
+
+		// Commits reachable from a remote include the oldest one in the range
+		pushed, err := git.IsPushed(commits[0].Hash)
+		if err != nil {
+			color.Red("Error: %v\n", err)
+			return
+		}
+		if pushed && !squashForce {
+			color.Red("Commit %s (%s) is already pushed; squashing it rewrites published history. Use --force to squash anyway.", commits[0].Hash[:7], commits[0].Subject)
+			return
+		}
+
+		// A squash of commits that cancel out would leave nothing to commit
+		if same, err := git.SameTree(base, "HEAD"); err != nil {
+			color.Red("Error: %v\n", err)
+			return
+		} else if same {
+			color.Yellow("Nothing to squash: the %d commits since %s add up to no change.", len(commits), base[:7])
+			return
+		}
+
+		repo := newRepository()
+		// Staged changes would silently end up in the squashed commit
+		if staged, err := repo.GetStagedFiles(); err != nil {
+			color.Red("Error reading staged files: %v\n", err)
+			return
+		} else if len(staged) > 0 {
+			color.Red("Error: there are staged changes; commit or unstage them before squashing")
+			return
+		}
+
+		gitDiff, err := git.GetRangeDiff(base, "HEAD")
+		if err != nil {
+			color.Red("Error getting diff: %v\n", err)
+			return
+		}
+
+		var messages strings.Builder
+		for _, c := range commits {
+			fmt.Fprintf(&messages, "- %s\n", strings.ReplaceAll(c.Message(), "\n", "\n  "))
+		}
+
+		service, err := newGenerator(cfg, repo, "squash", false)
+		if err != nil {
+			fmt.Printf("Error creating LLM service: %v\n", err)
+			return
+		}
+
+		s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+		s.Suffix = fmt.Sprintf(" Writing a message for %d commits using %s...", len(commits), cfg.AI.Provider)
+		s.Start()
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.AI.RequestTimeout())
+		defer cancel()
+
+		commitMsg, err := service.GenerateSquashMessage(ctx, messages.String(), gitDiff)
+		s.Stop()
+		if err != nil {
+			color.Red("Error generating commit message: %v\n", err)
+			return
+		}
+		color.Cyan("Usage: %s", service.Usage())
+
+		fmt.Printf("\n%s\n\n", commitMsg)
+
+		if squashDryRun {
+			return
+		}
+		if !squashYes && !askForConfirmation(fmt.Sprintf("Squash %d commits into this one?", len(commits))) {
+			return
+		}
+
+		head, err := git.RevParse("HEAD")
+		if err != nil {
+			color.Red("Error: %v\n", err)
+			return
+		}
+		if err := git.SoftReset(base); err != nil {
+			color.Red("Error: %v\n", err)
+			return
+		}
+		if err := repo.CreateCommit(commitMsg); err != nil {
+			color.Red("Error creating commit: %v\n", err)
+			if err := git.SoftReset(head); err != nil {
+				color.Yellow("Restore the original commits with 'git reset --soft %s'", head[:7])
+			}
+			return
+		}
+		color.Green("Squashed %d commits into one", len(commits))
+		if pushed {
+			color.Cyan("The branch was pushed; update it with 'git push --force-with-lease'")
+		}
+	},
+}
+
+func init() {
+	squashCmd.Flags().BoolVarP(&squashYes, "yes", "y", false, "Squash without confirmation")
+	squashCmd.Flags().BoolVar(&squashDryRun, "dry-run", false, "Print the message without squashing")
+	squashCmd.Flags().BoolVar(&squashForce, "force", false, "Allow squashing commits that are already pushed")
+	rootCmd.AddCommand(squashCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bitcs/commet/internal/usage"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var usageDays int
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Summarize token usage and cost",
+	Long: `Summarize the tokens and estimated cost of generation requests by day,
+repository and model. Every request is recorded in a local ledger; costs use
+built-in per-model prices which can be overridden with the ai.prices setting.
+
+Examples:
+  commet usage                     # Last 30 days
+  commet usage --days 7            # Last week`,
+	Run: func(cmd *cobra.Command, args []string) {
+		since := time.Now().AddDate(0, 0, -usageDays)
+		records, err := usage.ReadSince(since)
+		if err != nil {
+			fmt.Printf("Error reading usage ledger: %v\n", err)
+			return
+		}
+
+		if len(records) == 0 {
+			color.Yellow("No usage recorded in the last %d day(s)", usageDays)
+			return
+		}
+
+		var total usage.Usage
+		for _, r := range records {
+			total.Add(usage.Usage{InputTokens: r.InputTokens, OutputTokens: r.OutputTokens, Cost: r.Cost, Priced: r.Priced})
+		}
+		color.Cyan("Last %d day(s): %d request(s), %s", usageDays, len(records), total)
+
+		printUsageSummary("Day", usage.ByDay(records))
+		printUsageSummary("Repository", usage.Summarize(records, func(r usage.Record) string {
+			if r.Repo == "" {
+				return "(unknown)"
+			}
+			return r.Repo
+		}))
+		printUsageSummary("Model", usage.Summarize(records, func(r usage.Record) string {
+			return r.Provider + ":" + r.Model
+		}))
+	},
+}
+
+func printUsageSummary(title string, summaries []usage.Summary) {
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tREQUESTS\tINPUT\tOUTPUT\tCOST\n", title)
+	for _, s := range summaries {
+		cost := "-"
+		if s.Usage.Priced {
+			cost = fmt.Sprintf("$%.4f", s.Usage.Cost)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", s.Key, s.Requests, s.Usage.InputTokens, s.Usage.OutputTokens, cost)
+	}
+	w.Flush()
+}
+
+func init() {
+	usageCmd.Flags().IntVar(&usageDays, "days", 30, "Number of days to summarize")
+	rootCmd.AddCommand(usageCmd)
+}
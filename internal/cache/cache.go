@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// DefaultTTL is how long a cached response stays valid
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Store is a directory of cached responses keyed by content hash
+type Store struct {
+	dir string
+	ttl time.Duration
+}
+
+type entry struct {
+	CreatedAt time.Time `json:"created_at"`
+	Value     string    `json:"value"`
+}
+
+// Stats summarizes the contents of a store
+type Stats struct {
+	Dir     string
+	Entries int
+	Expired int
+	Bytes   int64
+}
+
+// Open returns the response store under the commet cache directory
+func Open() (*Store, error) {
+	base, err := config.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(base, "responses")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create cache directory: %w", err)
+	}
+	return &Store{dir: dir, ttl: DefaultTTL}, nil
+}
+
+// Key hashes the given parts into a cache key
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// Length prefixes keep ("ab", "c") and ("a", "bc") distinct
+		fmt.Fprintf(h, "%d:%s;", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached value for key if present and not expired
+func (s *Store) Get(key string) (string, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return "", false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || time.Since(e.CreatedAt) > s.ttl {
+		return "", false
+	}
+	return e.Value, true
+}
+
+// Put stores value under key. Writes are rare, so they also sweep out
+// expired entries.
+func (s *Store) Put(key, value string) error {
+	data, err := json.Marshal(entry{CreatedAt: time.Now(), Value: value})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path(key), data, 0600); err != nil {
+		return err
+	}
+	_, err = s.Prune()
+	return err
+}
+
+// Prune removes expired entries and returns how many were removed
+func (s *Store) Prune() (int, error) {
+	files, err := s.files()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil || time.Since(info.ModTime()) <= s.ttl {
+			continue
+		}
+		if err := os.Remove(f); err != nil {
+			return removed, fmt.Errorf("could not remove cache entry: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Clear removes all cached entries and returns how many were removed
+func (s *Store) Clear() (int, error) {
+	files, err := s.files()
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			return 0, fmt.Errorf("could not remove cache entry: %w", err)
+		}
+	}
+	return len(files), nil
+}
+
+// Stats reports the number and size of cached entries
+func (s *Store) Stats() (Stats, error) {
+	stats := Stats{Dir: s.dir}
+	files, err := s.files()
+	if err != nil {
+		return stats, err
+	}
+
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if time.Since(info.ModTime()) > s.ttl {
+			stats.Expired++
+		}
+	}
+	return stats, nil
+}
+
+func (s *Store) files() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read cache directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, filepath.Join(s.dir, e.Name()))
+		}
+	}
+	return files, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return &Store{dir: t.TempDir(), ttl: time.Hour}
+}
+
+// age backdates the entry stored under key by d
+func age(t *testing.T, s *Store, key string, d time.Duration) {
+	t.Helper()
+	data, err := json.Marshal(entry{CreatedAt: time.Now().Add(-d), Value: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.path(key), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	then := time.Now().Add(-d)
+	if err := os.Chtimes(s.path(key), then, then); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error("Key() collides when parts are split differently")
+	}
+	if Key("a", "b") != Key("a", "b") {
+		t.Error("Key() is not deterministic")
+	}
+}
+
+func TestGetHitMissAndExpiry(t *testing.T) {
+	s := newTestStore(t)
+
+	if _, ok := s.Get("missing"); ok {
+		t.Error("Get() of a missing key hit")
+	}
+
+	if err := s.Put("key", "message"); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := s.Get("key"); !ok || got != "message" {
+		t.Errorf("Get() = %q, %v, want the stored message", got, ok)
+	}
+
+	age(t, s, "stale", 2*time.Hour)
+	if _, ok := s.Get("stale"); ok {
+		t.Error("Get() of an expired entry hit")
+	}
+
+	if err := os.WriteFile(s.path("corrupt"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("corrupt"); ok {
+		t.Error("Get() of a corrupt entry hit")
+	}
+}
+
+func TestPruneStatsAndClear(t *testing.T) {
+	s := newTestStore(t)
+	age(t, s, "fresh", time.Minute)
+	age(t, s, "stale", 2*time.Hour)
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 2 || stats.Expired != 1 || stats.Bytes == 0 {
+		t.Errorf("Stats() = %+v, want 2 entries with 1 expired", stats)
+	}
+
+	if removed, err := s.Prune(); err != nil || removed != 1 {
+		t.Errorf("Prune() = %d, %v, want 1", removed, err)
+	}
+	if _, ok := s.Get("fresh"); !ok {
+		t.Error("Prune() removed a fresh entry")
+	}
+
+	// Put sweeps expired entries as well
+	age(t, s, "stale", 2*time.Hour)
+	if err := s.Put("new", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path("stale")); !os.IsNotExist(err) {
+		t.Errorf("expired entry left after Put(): %v", err)
+	}
+
+	if removed, err := s.Clear(); err != nil || removed != 2 {
+		t.Errorf("Clear() = %d, %v, want 2", removed, err)
+	}
+	if stats, _ := s.Stats(); stats.Entries != 0 {
+		t.Errorf("Stats() after Clear() = %+v, want no entries", stats)
+	}
+}
@@ -0,0 +1,207 @@
+// Package changelog renders commit history as Keep a Changelog sections.
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/message"
+)
+
+// Section is a Keep a Changelog change category
+type Section string
+
+const (
+	Added      Section = "Added"
+	Changed    Section = "Changed"
+	Deprecated Section = "Deprecated"
+	Removed    Section = "Removed"
+	Fixed      Section = "Fixed"
+	Security   Section = "Security"
+	// Skip marks commits left out of the changelog (chores, CI, tests...)
+	Skip Section = "Skip"
+)
+
+// Sections lists the categories in the order they are rendered
+var Sections = []Section{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// ParseSection accepts a category name in any case
+func ParseSection(s string) (Section, bool) {
+	for _, section := range append(Sections, Skip) {
+		if strings.EqualFold(strings.TrimSpace(s), string(section)) {
+			return section, true
+		}
+	}
+	return "", false
+}
+
+// Entry is one line of the changelog
+type Entry struct {
+	Section  Section
+	Text     string
+	Hash     string
+	Breaking bool
+}
+
+var (
+	typeSections = map[string]Section{
+		"feat":     Added,
+		"fix":      Fixed,
+		"perf":     Changed,
+		"refactor": Changed,
+		"revert":   Removed,
+		"docs":     Skip,
+		"style":    Skip,
+		"test":     Skip,
+		"build":    Skip,
+		"ci":       Skip,
+		"chore":    Skip,
+	}
+
+	mergePullPattern = regexp.MustCompile(`^Merge pull request (#\d+) from \S+$`)
+	mergePattern     = regexp.MustCompile(`^Merge (branch|remote-tracking branch|tag) `)
+)
+
+// Classify derives the entry of a commit from its conventional commit header.
+// It reports false when the message is not conventional and needs another
+// way of classification.
+func Classify(c git.Commit) (Entry, bool) {
+	text := c.Message()
+
+	// Merged pull requests are described by the title in their body
+	if m := mergePullPattern.FindStringSubmatch(c.Subject); m != nil && c.Body != "" {
+		title, _, _ := strings.Cut(c.Body, "\n")
+		text = title + " (" + m[1] + ")"
+	}
+
+	msg, ok := message.ParseConventional(text)
+	entry := Entry{Text: message.Capitalize(msg.Subject), Hash: short(c.Hash), Breaking: msg.IsBreaking()}
+	if !ok {
+		return entry, false
+	}
+
+	section, known := typeSections[msg.Type]
+	if !known {
+		return entry, false
+	}
+	if entry.Breaking && section == Skip {
+		section = Changed
+	}
+	entry.Section = section
+	return entry, true
+}
+
+// IsMerge reports whether a commit is a plain branch merge with nothing to
+// report beyond the merged commits
+func IsMerge(c git.Commit) bool {
+	return mergePattern.MatchString(c.Subject)
+}
+
+// IsPullRequestMerge reports whether a commit merges a GitHub pull request
+func IsPullRequestMerge(c git.Commit) bool {
+	return mergePullPattern.MatchString(c.Subject)
+}
+
+// Render formats entries as a changelog section for version, which is
+// "Unreleased" when empty
+func Render(version string, date time.Time, entries []Entry) string {
+	var b strings.Builder
+	if version == "" {
+		b.WriteString(unreleasedHeading + "\n")
+	} else {
+		fmt.Fprintf(&b, "## [%s] - %s\n", strings.TrimPrefix(version, "v"), date.Format("2006-01-02"))
+	}
+
+	for _, section := range Sections {
+		var lines []string
+		for _, e := range entries {
+			if e.Section != section {
+				continue
+			}
+			line := "- "
+			if e.Breaking {
+				line += "**Breaking:** "
+			}
+			line += e.Text
+			if e.Hash != "" {
+				line += " (" + e.Hash + ")"
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", section, strings.Join(lines, "\n"))
+		}
+	}
+	return b.String()
+}
+
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// unreleasedHeading starts the section of changes not released yet
+const unreleasedHeading = "## [Unreleased]"
+
+// Prepend inserts section above the newest release in the changelog at path,
+// creating the file with the standard header when it does not exist. An
+// existing Unreleased section is replaced, since section was generated from
+// the same commits.
+func Prepend(path, section string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		content = header
+	}
+
+	content = removeUnreleased(content)
+
+	// Keep the title and introduction above the first release heading
+	var updated string
+	if i := strings.Index(content, "\n## "); i >= 0 {
+		updated = strings.TrimRight(content[:i], "\n") + "\n\n" + section + "\n" + content[i+1:]
+	} else {
+		updated = strings.TrimRight(content, "\n") + "\n\n" + section
+	}
+	if !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return nil
+}
+
+// removeUnreleased drops the Unreleased section, from its heading up to the
+// next release heading or the end of the file
+func removeUnreleased(content string) string {
+	start := strings.Index(content, "\n"+unreleasedHeading)
+	if start < 0 {
+		return content
+	}
+	start++
+	end := len(content)
+	if next := strings.Index(content[start+len(unreleasedHeading):], "\n## "); next >= 0 {
+		end = start + len(unreleasedHeading) + next + 1
+	}
+	return content[:start] + content[end:]
+}
+
+func short(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
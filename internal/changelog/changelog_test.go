@@ -0,0 +1,86 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const release = `## [1.0.0] - 2024-01-01
+
+### Added
+
+- First release
+`
+
+func TestPrepend(t *testing.T) {
+	unreleased := "## [Unreleased]\n\n### Fixed\n\n- Old fix\n"
+	section := "## [Unreleased]\n\n### Added\n\n- New feature\n"
+
+	tests := []struct {
+		name     string
+		existing string
+		section  string
+		want     string
+	}{
+		{
+			name:    "new file",
+			section: section,
+			want:    header + "\n" + section,
+		},
+		{
+			name:     "above the newest release",
+			existing: header + "\n" + release,
+			section:  section,
+			want:     header + "\n" + section + "\n" + release,
+		},
+		{
+			name:     "replaces unreleased",
+			existing: header + "\n" + unreleased + "\n" + release,
+			section:  section,
+			want:     header + "\n" + section + "\n" + release,
+		},
+		{
+			name:     "replaces the only unreleased section",
+			existing: header + "\n" + unreleased,
+			section:  section,
+			want:     header + "\n" + section,
+		},
+		{
+			name:     "release replaces unreleased",
+			existing: header + "\n" + unreleased + "\n" + release,
+			section:  "## [1.1.0] - 2024-02-01\n\n### Added\n\n- New feature\n",
+			want:     header + "\n## [1.1.0] - 2024-02-01\n\n### Added\n\n- New feature\n\n" + release,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := Prepend(path, tt.section); err != nil {
+				t.Fatalf("Prepend() error = %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("changelog =\n%s\nwant\n%s", got, tt.want)
+			}
+
+			// Running again leaves a single copy of the section
+			if err := Prepend(path, tt.section); err != nil {
+				t.Fatal(err)
+			}
+			if again, _ := os.ReadFile(path); tt.section == section && string(again) != tt.want {
+				t.Errorf("changelog after a second run =\n%s\nwant\n%s", again, tt.want)
+			}
+		})
+	}
+}
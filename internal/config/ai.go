@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
+// DefaultTimeout bounds a single generation request when ai.timeout is unset
+const DefaultTimeout = 30 * time.Second
+
 type Provider string
 
 const (
@@ -12,12 +16,41 @@ const (
 	ProviderClaude Provider = "claude"
 	ProviderGoogle Provider = "google"
 	ProviderGroq   Provider = "groq"
+	// ProviderFake returns canned responses without network access; it is
+	// meant for tests and demos and is not offered in the interactive setup
+	ProviderFake Provider = "fake"
 )
 
 type AIConfig struct {
-	Provider Provider `mapstructure:"provider" yaml:"provider"`
-	APIKey   string   `mapstructure:"api_key" yaml:"api_key"`
-	Model    string   `mapstructure:"model" yaml:"model"`
+	Provider Provider          `mapstructure:"provider" yaml:"provider"`
+	APIKeys  map[string]string `mapstructure:"api_keys" yaml:"api_keys"`
+	Model    string            `mapstructure:"model" yaml:"model"`
+	Style    Style             `mapstructure:"style" yaml:"style"`
+
+	// Generation parameters; unset values leave the provider default in place
+	Temperature  *float64      `mapstructure:"temperature" yaml:"temperature"`
+	TopP         *float64      `mapstructure:"top_p" yaml:"top_p"`
+	MaxTokens    int           `mapstructure:"max_tokens" yaml:"max_tokens"`
+	Timeout      time.Duration `mapstructure:"timeout" yaml:"timeout"`
+	SystemPrompt string        `mapstructure:"system_prompt" yaml:"system_prompt"`
+
+	// MaxSubjectLength caps the subject line of generated messages
+	MaxSubjectLength int `mapstructure:"max_subject_length" yaml:"max_subject_length"`
+
+	// Retries is the number of attempts per provider for transient errors
+	Retries int `mapstructure:"retries" yaml:"retries"`
+	// Fallbacks are tried in order when the primary provider keeps failing
+	Fallbacks []Fallback `mapstructure:"fallbacks" yaml:"fallbacks"`
+	// Prices override the built-in per-model price table
+	Prices []Price `mapstructure:"prices" yaml:"prices"`
+
+	// APIKey is the key of the selected provider, resolved from APIKeys
+	APIKey string `mapstructure:"-" yaml:"-"`
+}
+
+// Providers returns all supported providers
+func Providers() []Provider {
+	return []Provider{ProviderOpenAI, ProviderClaude, ProviderGoogle, ProviderGroq}
 }
 
 func (p Provider) String() string {
@@ -26,7 +59,7 @@ func (p Provider) String() string {
 
 func (p Provider) IsValid() bool {
 	switch p {
-	case ProviderOpenAI, ProviderClaude, ProviderGoogle, ProviderGroq:
+	case ProviderOpenAI, ProviderClaude, ProviderGoogle, ProviderGroq, ProviderFake:
 		return true
 	default:
 		return false
@@ -36,42 +69,49 @@ func (p Provider) IsValid() bool {
 func ParseProvider(s string) (Provider, error) {
 	provider := Provider(strings.ToLower(s))
 	if !provider.IsValid() {
-		return "", fmt.Errorf("invalid provider: %s (valid options: openai, claude, google, groq)", s)
+		return "", fmt.Errorf("invalid provider: %s (valid options: openai, claude, google, groq, fake)", s)
 	}
 	return provider, nil
 }
 
-// GetAvailableModels returns the available models for each provider
+// RequiresAPIKey reports whether the provider needs an API key to generate
+func (p Provider) RequiresAPIKey() bool {
+	return p != ProviderFake
+}
+
+// GetAvailableModels returns the built-in models for each provider. It is
+// used as the offline fallback when a provider's model list cannot be fetched.
 func GetAvailableModels(provider Provider) []string {
 	switch provider {
 	case ProviderOpenAI:
 		return []string{
 			"gpt-4o",
-			"gpt-4-turbo",
-			"gpt-4",
-			"gpt-3.5-turbo",
+			"gpt-4o-mini",
+			"gpt-4.1",
+			"gpt-4.1-mini",
 		}
 	case ProviderClaude:
 		return []string{
-			"claude-3-5-sonnet-20241022",
-			"claude-3-opus-20240229",
-			"claude-3-sonnet-20240229",
-			"claude-3-haiku-20240307",
+			"claude-sonnet-4-20250514",
+			"claude-3-7-sonnet-latest",
+			"claude-3-5-haiku-latest",
+			"claude-opus-4-20250514",
 		}
 	case ProviderGoogle:
 		return []string{
-			"gemini-1.5-pro",
-			"gemini-1.5-flash",
-			"gemini-pro",
-			"gemini-pro-vision",
+			"gemini-2.5-flash",
+			"gemini-2.5-pro",
+			"gemini-2.0-flash",
+			"gemini-2.0-flash-lite",
 		}
 	case ProviderGroq:
 		return []string{
-			"llama-3.1-70b-versatile",
+			"llama-3.3-70b-versatile",
 			"llama-3.1-8b-instant",
-			"mixtral-8x7b-32768",
-			"gemma-7b-it",
+			"gemma2-9b-it",
 		}
+	case ProviderFake:
+		return []string{"fake"}
 	default:
 		return []string{}
 	}
@@ -93,6 +133,123 @@ func (c *AIConfig) SetDefaults() {
 	if c.Provider == "" {
 		c.Provider = ProviderOpenAI
 	}
+	if c.Style == "" {
+		c.Style = StyleConventional
+	}
+	if c.APIKey == "" {
+		c.APIKey = c.APIKeys[c.Provider.String()]
+	}
+}
+
+// DefaultRetries is the number of attempts per provider when ai.retries is unset
+const DefaultRetries = 3
+
+// Fallback is a provider/model pair used when the primary provider fails.
+// An empty model selects the provider's default model.
+type Fallback struct {
+	Provider Provider `mapstructure:"provider" yaml:"provider"`
+	Model    string   `mapstructure:"model" yaml:"model"`
+}
+
+func (f Fallback) String() string {
+	if f.Model == "" {
+		return f.Provider.String()
+	}
+	return f.Provider.String() + ":" + f.Model
+}
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	Model  string  `mapstructure:"model" yaml:"model"`
+	Input  float64 `mapstructure:"input" yaml:"input"`
+	Output float64 `mapstructure:"output" yaml:"output"`
+}
+
+func (p Price) String() string {
+	return fmt.Sprintf("%s=%g/%g", p.Model, p.Input, p.Output)
+}
+
+// ParsePrices parses a comma separated list of model=input/output entries
+func ParsePrices(s string) ([]Price, error) {
+	prices := []Price{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, cost, ok := strings.Cut(entry, "=")
+		input, output, ok2 := strings.Cut(cost, "/")
+		if !ok || !ok2 || model == "" {
+			return nil, fmt.Errorf("invalid price %q (expected model=input/output)", entry)
+		}
+		var p Price
+		if _, err := fmt.Sscanf(input+" "+output, "%g %g", &p.Input, &p.Output); err != nil || p.Input < 0 || p.Output < 0 {
+			return nil, fmt.Errorf("invalid price %q (expected non-negative numbers)", entry)
+		}
+		p.Model = model
+		prices = append(prices, p)
+	}
+	return prices, nil
+}
+
+// ParseFallbacks parses a comma separated list of provider[:model] entries
+func ParseFallbacks(s string) ([]Fallback, error) {
+	fallbacks := []Fallback{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, model, _ := strings.Cut(entry, ":")
+		provider, err := ParseProvider(name)
+		if err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, Fallback{Provider: provider, Model: model})
+	}
+	return fallbacks, nil
+}
+
+// DefaultMaxSubjectLength is used when ai.max_subject_length is unset
+const DefaultMaxSubjectLength = 72
+
+// SubjectLimit returns the configured subject length limit or the default
+func (c *AIConfig) SubjectLimit() int {
+	if c.MaxSubjectLength <= 0 {
+		return DefaultMaxSubjectLength
+	}
+	return c.MaxSubjectLength
+}
+
+// MaxAttempts returns the configured attempts per provider or DefaultRetries
+func (c *AIConfig) MaxAttempts() int {
+	if c.Retries <= 0 {
+		return DefaultRetries
+	}
+	return c.Retries
+}
+
+// RequestTimeout returns the configured request timeout or DefaultTimeout
+func (c *AIConfig) RequestTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return c.Timeout
+}
+
+// SetProvider switches the provider and selects its stored API key
+func (c *AIConfig) SetProvider(p Provider) {
+	c.Provider = p
+	c.APIKey = c.APIKeys[p.String()]
+}
+
+// SetAPIKey stores the key for the selected provider
+func (c *AIConfig) SetAPIKey(key string) {
+	if c.APIKeys == nil {
+		c.APIKeys = map[string]string{}
+	}
+	c.APIKeys[c.Provider.String()] = key
+	c.APIKey = key
 }
 
 func (c *AIConfig) MaskAPIKey() string {
@@ -3,13 +3,23 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	AI  AIConfig  `mapstructure:"ai" yaml:"ai"`
-	Git GitConfig `mapstructure:"git" yaml:"git"`
+	Version  int                `mapstructure:"version" yaml:"version"`
+	AI       AIConfig           `mapstructure:"ai" yaml:"ai"`
+	Git      GitConfig          `mapstructure:"git" yaml:"git"`
+	Forge    ForgeConfig        `mapstructure:"forge" yaml:"forge"`
+	Profiles map[string]Profile `mapstructure:"profiles" yaml:"profiles"`
+
+	// ActiveProfile is the profile overlaid on the base settings, if any
+	ActiveProfile string `mapstructure:"-" yaml:"-"`
+	// base holds the settings without the active profile, so Save can tell
+	// which values the profile overrides
+	base *Config
 }
 
 func Load() (*Config, error) {
@@ -19,6 +29,12 @@ func Load() (*Config, error) {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
+	} else if migrated, err := migrateFile(viper.ConfigFileUsed()); err != nil {
+		return nil, err
+	} else if migrated {
+		if err := viper.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading migrated config file: %w", err)
+		}
 	}
 
 	if err := viper.Unmarshal(&cfg); err != nil {
@@ -30,34 +46,138 @@ func Load() (*Config, error) {
 		cfg.Git.UseAI = true
 	}
 
+	// An explicit --profile / COMMET_PROFILE wins over remote URL matching
+	profile := selectedProfile
+	if profile == "" {
+		profile = os.Getenv("COMMET_PROFILE")
+	}
+	if profile == "" {
+		profile = viper.GetString("profile")
+	}
+	if profile == "" {
+		profile = cfg.matchProfile(getRemoteURLs())
+	}
+	if profile != "" {
+		base := cfg.clone()
+		base.SetDefaults()
+		cfg.base = &base
+		if err := cfg.applyProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
 	cfg.SetDefaults()
 
 	return &cfg, nil
 }
 
+// Save writes the settings back to the config file. When a profile is
+// active, only the values the profile sets, or that now differ from the base
+// settings, are stored in the profile; the base is left alone.
 func (c *Config) Save() error {
-	viper.Set("ai.provider", c.AI.Provider)
-	viper.Set("ai.api_key", c.AI.APIKey)
-	viper.Set("ai.model", c.AI.Model)
-	viper.Set("git.auto_stage", c.Git.AutoStage)
-	viper.Set("git.show_diff", c.Git.ShowDiff)
-	viper.Set("git.confirm_push", c.Git.ConfirmPush)
-	viper.Set("git.direct_commit", c.Git.DirectCommit)
-	viper.Set("git.use_ai", c.Git.UseAI)
-	viper.Set("git.interactive", c.Git.Interactive)
-
-	configPath := viper.ConfigFileUsed()
-	if configPath == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("could not get home directory: %w", err)
-		}
-		configPath = fmt.Sprintf("%s/.commet.yaml", home)
+	viper.Set("version", CurrentVersion)
+	c.set("ai.provider", c.AI.Provider)
+	for provider, key := range c.AI.APIKeys {
+		c.set("ai.api_keys."+provider, key)
+	}
+	c.set("ai.model", c.AI.Model)
+	c.set("ai.style", c.AI.Style)
+	if c.AI.Temperature != nil {
+		c.set("ai.temperature", *c.AI.Temperature)
+	}
+	if c.AI.TopP != nil {
+		c.set("ai.top_p", *c.AI.TopP)
+	}
+	if c.AI.MaxTokens > 0 {
+		c.set("ai.max_tokens", c.AI.MaxTokens)
+	}
+	if c.AI.Timeout > 0 {
+		c.set("ai.timeout", c.AI.Timeout.String())
+	}
+	if c.AI.SystemPrompt != "" {
+		c.set("ai.system_prompt", c.AI.SystemPrompt)
+	}
+	if c.AI.MaxSubjectLength > 0 {
+		c.set("ai.max_subject_length", c.AI.MaxSubjectLength)
+	}
+	if c.AI.Retries > 0 {
+		c.set("ai.retries", c.AI.Retries)
+	}
+	if len(c.AI.Fallbacks) > 0 {
+		c.set("ai.fallbacks", fallbackSettings(c.AI.Fallbacks))
+	}
+	if len(c.AI.Prices) > 0 {
+		c.set("ai.prices", priceSettings(c.AI.Prices))
+	}
+	c.set("git.auto_stage", c.Git.AutoStage)
+	c.set("git.show_diff", c.Git.ShowDiff)
+	c.set("git.confirm_push", c.Git.ConfirmPush)
+	c.set("git.direct_commit", c.Git.DirectCommit)
+	c.set("git.use_ai", c.Git.UseAI)
+	c.set("git.interactive", c.Git.Interactive)
+	// Written even when empty so a gate that was set can be turned off
+	c.set("git.review_gate", c.Git.ReviewGate)
+	if c.Forge.Type != "" {
+		c.set("forge.type", c.Forge.Type)
+	}
+	if c.Forge.BaseURL != "" {
+		c.set("forge.base_url", c.Forge.BaseURL)
+	}
+	if c.Forge.Token != "" {
+		c.set("forge.token", c.Forge.Token)
+	}
+
+	configPath, err := FilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
 	}
 
 	return viper.WriteConfigAs(configPath)
 }
 
+// set stores a setting for Save, under the active profile when there is one.
+// Base values the profile does not override stay out of the profile.
+func (c *Config) set(name string, value interface{}) {
+	if c.ActiveProfile == "" {
+		viper.Set(name, value)
+		return
+	}
+
+	prefix := c.prefix()
+	if !viper.IsSet(prefix+name) && c.base != nil {
+		if key, err := LookupKey(name); err == nil && fmt.Sprint(key.Value(c)) == fmt.Sprint(key.Value(c.base)) {
+			return
+		}
+	}
+	viper.Set(prefix+name, value)
+}
+
+// prefix is where Save stores settings: the active profile or the base
+func (c *Config) prefix() string {
+	if c.ActiveProfile == "" {
+		return ""
+	}
+	return "profiles." + c.ActiveProfile + "."
+}
+
+// clone copies the config deeply enough that overlaying a profile on the
+// copy leaves the original untouched
+func (c Config) clone() Config {
+	if c.AI.APIKeys != nil {
+		keys := make(map[string]string, len(c.AI.APIKeys))
+		for provider, key := range c.AI.APIKeys {
+			keys[provider] = key
+		}
+		c.AI.APIKeys = keys
+	}
+	c.AI.Fallbacks = append([]Fallback(nil), c.AI.Fallbacks...)
+	c.AI.Prices = append([]Price(nil), c.AI.Prices...)
+	return c
+}
+
 func (c *Config) MaskAPIKey() string {
 	return c.AI.MaskAPIKey()
 }
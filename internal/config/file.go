@@ -0,0 +1,209 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// FilePath returns the config file in use, or the preferred location when no
+// file has been loaded yet
+func FilePath() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+
+	paths := SearchPaths()
+	if len(paths) == 0 {
+		return "", fmt.Errorf("could not determine config file location")
+	}
+	return paths[0], nil
+}
+
+// SetValue validates value for key and writes it to the config file
+func SetValue(name, value string) error {
+	key, err := LookupKey(name)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := key.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	return updateFile(func(settings map[string]interface{}) {
+		setNested(settings, strings.Split(key.Name, "."), parsed)
+	})
+}
+
+// UnsetValue removes key from the config file so its default applies again
+func UnsetValue(name string) error {
+	key, err := LookupKey(name)
+	if err != nil {
+		return err
+	}
+
+	return updateFile(func(settings map[string]interface{}) {
+		deleteNested(settings, strings.Split(key.Name, "."))
+	})
+}
+
+// FileValue returns the raw value stored in the config file for key
+func FileValue(name string) (interface{}, bool) {
+	return viper.Get(name), viper.IsSet(name)
+}
+
+// Validate checks the config file at path for unknown keys and invalid values.
+// A missing file is valid.
+func Validate(path string) []error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return []error{fmt.Errorf("error reading config file: %w", err)}
+	}
+
+	var errs []error
+	allKeys := v.AllKeys()
+	sort.Strings(allKeys)
+	for _, name := range allKeys {
+		key, err := LookupKey(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if key.parse == nil {
+			continue
+		}
+		if list, ok := v.Get(name).([]interface{}); ok {
+			if strings.HasSuffix(name, "ai.fallbacks") {
+				if err := validateFallbacks(name, list); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if strings.HasSuffix(name, "ai.prices") {
+				if err := validatePrices(name, list); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+		if _, err := key.Parse(fmt.Sprint(v.Get(name))); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if profile := v.GetString("profile"); profile != "" && !v.IsSet("profiles."+profile) {
+		errs = append(errs, fmt.Errorf("profile: %s is not defined under profiles", profile))
+	}
+
+	return errs
+}
+
+func validateFallbacks(name string, list []interface{}) error {
+	for i, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid value for %s: entry %d must have a provider and an optional model", name, i+1)
+		}
+		if _, err := ParseProvider(fmt.Sprint(entry["provider"])); err != nil {
+			return fmt.Errorf("invalid value for %s: entry %d: %w", name, i+1, err)
+		}
+	}
+	return nil
+}
+
+func validatePrices(name string, list []interface{}) error {
+	for i, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok || fmt.Sprint(entry["model"]) == "" || entry["model"] == nil {
+			return fmt.Errorf("invalid value for %s: entry %d must have a model, input and output price", name, i+1)
+		}
+		for _, field := range []string{"input", "output"} {
+			if _, err := parseRange(0, 1e6)(fmt.Sprint(entry[field])); err != nil {
+				return fmt.Errorf("invalid value for %s: entry %d %s: %w", name, i+1, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// updateFile applies fn to the settings stored in the config file and writes
+// them back, leaving values from flags and the environment out of the file
+func updateFile(fn func(map[string]interface{})) error {
+	path, err := FilePath()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+	} else if migrated, err := migrateFile(path); err != nil {
+		return err
+	} else if migrated {
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("error reading migrated config file: %w", err)
+		}
+	}
+
+	settings := v.AllSettings()
+	fn(settings)
+	settings["version"] = CurrentVersion
+
+	out := viper.New()
+	out.SetConfigType("yaml")
+	if err := out.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("error updating config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+	if err := out.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+
+	// Keep the global view in sync for the rest of this process
+	viper.SetConfigFile(path)
+	return viper.ReadInConfig()
+}
+
+func setNested(m map[string]interface{}, path []string, value interface{}) {
+	for _, part := range path[:len(path)-1] {
+		next, ok := m[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[part] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
+
+func deleteNested(m map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+	next, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	deleteNested(next, path[1:])
+	if len(next) == 0 {
+		delete(m, path[0])
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ForgeType is the hosting service pull requests are opened on
+type ForgeType string
+
+const (
+	ForgeGitHub ForgeType = "github"
+	ForgeGitLab ForgeType = "gitlab"
+)
+
+// ForgeConfig configures pull request creation. Empty values are detected
+// from the repository's remote URL.
+type ForgeConfig struct {
+	Type ForgeType `mapstructure:"type" yaml:"type"`
+	// BaseURL is the REST API root, e.g. https://github.example.com/api/v3
+	BaseURL string `mapstructure:"base_url" yaml:"base_url"`
+	// Token falls back to GH_TOKEN/GITHUB_TOKEN or GITLAB_TOKEN/GLAB_TOKEN
+	Token string `mapstructure:"token" yaml:"token"`
+}
+
+func (t ForgeType) String() string {
+	return string(t)
+}
+
+func ParseForgeType(s string) (ForgeType, error) {
+	t := ForgeType(strings.ToLower(s))
+	switch t {
+	case ForgeGitHub, ForgeGitLab:
+		return t, nil
+	default:
+		return "", fmt.Errorf("invalid forge type: %s (valid options: github, gitlab)", s)
+	}
+}
+
+// tokenEnv lists the environment variables the gh and glab CLIs read
+var tokenEnv = map[ForgeType][]string{
+	ForgeGitHub: {"GH_TOKEN", "GITHUB_TOKEN"},
+	ForgeGitLab: {"GITLAB_TOKEN", "GLAB_TOKEN"},
+}
+
+// TokenEnv returns the environment variables searched for a token
+func TokenEnv(t ForgeType) []string {
+	return tokenEnv[t]
+}
+
+// ResolveToken returns the configured token or the first one set in the
+// environment for the given forge
+func (c *ForgeConfig) ResolveToken(t ForgeType) string {
+	if c.Token != "" {
+		return c.Token
+	}
+	for _, name := range tokenEnv[t] {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+func (c *ForgeConfig) MaskToken() string {
+	return (&AIConfig{APIKey: c.Token}).MaskAPIKey()
+}
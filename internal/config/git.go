@@ -1,12 +1,15 @@
 package config
 
 type GitConfig struct {
-	AutoStage     bool `mapstructure:"auto_stage" yaml:"auto_stage"`
-	ShowDiff      bool `mapstructure:"show_diff" yaml:"show_diff"`
-	ConfirmPush   bool `mapstructure:"confirm_push" yaml:"confirm_push"`
-	DirectCommit  bool `mapstructure:"direct_commit" yaml:"direct_commit"`
-	UseAI         bool `mapstructure:"use_ai" yaml:"use_ai"`
-	Interactive   bool `mapstructure:"interactive" yaml:"interactive"`
+	AutoStage    bool `mapstructure:"auto_stage" yaml:"auto_stage"`
+	ShowDiff     bool `mapstructure:"show_diff" yaml:"show_diff"`
+	ConfirmPush  bool `mapstructure:"confirm_push" yaml:"confirm_push"`
+	DirectCommit bool `mapstructure:"direct_commit" yaml:"direct_commit"`
+	UseAI        bool `mapstructure:"use_ai" yaml:"use_ai"`
+	Interactive  bool `mapstructure:"interactive" yaml:"interactive"`
+	// ReviewGate aborts commits when the AI review reports a finding at
+	// least this severe; empty disables the review
+	ReviewGate Severity `mapstructure:"review_gate" yaml:"review_gate,omitempty"`
 }
 
 func (c *GitConfig) SetDefaults() {
@@ -0,0 +1,446 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Key describes a single configuration setting addressable from the CLI
+type Key struct {
+	Name        string
+	Description string
+	// Secret values are masked when displayed
+	Secret bool
+	// Profile reports whether the key may be overridden inside a profile
+	Profile bool
+
+	parse func(string) (interface{}, error)
+	get   func(*Config) interface{}
+}
+
+var keys = append(append([]Key{
+	{
+		Name:        "ai.provider",
+		Description: "AI provider (openai, claude, google, groq)",
+		Profile:     true,
+		parse:       func(s string) (interface{}, error) { p, err := ParseProvider(s); return p.String(), err },
+		get:         func(c *Config) interface{} { return c.AI.Provider },
+	},
+}, apiKeyKeys()...), []Key{
+	{
+		Name:        "ai.model",
+		Description: "AI model to use (empty for the provider default)",
+		Profile:     true,
+		parse:       parseString,
+		get:         func(c *Config) interface{} { return c.AI.Model },
+	},
+	{
+		Name:        "ai.style",
+		Description: "Commit message style (conventional, simple, detailed)",
+		Profile:     true,
+		parse:       func(s string) (interface{}, error) { st, err := ParseStyle(s); return st.String(), err },
+		get:         func(c *Config) interface{} { return c.AI.Style },
+	},
+	{
+		Name:        "ai.temperature",
+		Description: "Sampling temperature between 0 and 2 (empty for the provider default)",
+		Profile:     true,
+		parse:       parseRange(0, 2),
+		get:         func(c *Config) interface{} { return FormatOptional(c.AI.Temperature) },
+	},
+	{
+		Name:        "ai.top_p",
+		Description: "Nucleus sampling probability between 0 and 1 (empty for the provider default)",
+		Profile:     true,
+		parse:       parseRange(0, 1),
+		get:         func(c *Config) interface{} { return FormatOptional(c.AI.TopP) },
+	},
+	{
+		Name:        "ai.max_tokens",
+		Description: "Maximum tokens to generate (0 for the provider default)",
+		Profile:     true,
+		parse:       parseNonNegativeInt,
+		get:         func(c *Config) interface{} { return c.AI.MaxTokens },
+	},
+	{
+		Name:        "ai.timeout",
+		Description: "Timeout for a generation request, e.g. 90s or 2m",
+		Profile:     true,
+		parse:       parseDuration,
+		get:         func(c *Config) interface{} { return c.AI.RequestTimeout() },
+	},
+	{
+		Name:        "ai.system_prompt",
+		Description: "Extra system instructions sent with every request",
+		Profile:     true,
+		parse:       parseString,
+		get:         func(c *Config) interface{} { return c.AI.SystemPrompt },
+	},
+	{
+		Name:        "ai.max_subject_length",
+		Description: "Maximum subject line length of generated messages",
+		Profile:     true,
+		parse:       parseNonNegativeInt,
+		get:         func(c *Config) interface{} { return c.AI.SubjectLimit() },
+	},
+	{
+		Name:        "ai.retries",
+		Description: "Attempts per provider on rate limits and server errors",
+		Profile:     true,
+		parse:       parseNonNegativeInt,
+		get:         func(c *Config) interface{} { return c.AI.MaxAttempts() },
+	},
+	{
+		Name:        "ai.fallbacks",
+		Description: "Ordered provider[:model] list tried when the provider fails",
+		Profile:     true,
+		parse:       parseFallbacks,
+		get:         func(c *Config) interface{} { return formatFallbacks(c.AI.Fallbacks) },
+	},
+	{
+		Name:        "ai.prices",
+		Description: "Price overrides per million tokens as model=input/output,...",
+		Profile:     true,
+		parse:       parsePrices,
+		get:         func(c *Config) interface{} { return formatPrices(c.AI.Prices) },
+	},
+	{
+		Name:        "git.auto_stage",
+		Description: "Stage all changes when nothing is staged",
+		Profile:     true,
+		parse:       parseBool,
+		get:         func(c *Config) interface{} { return c.Git.AutoStage },
+	},
+	{
+		Name:        "git.show_diff",
+		Description: "Show the diff before committing",
+		Profile:     true,
+		parse:       parseBool,
+		get:         func(c *Config) interface{} { return c.Git.ShowDiff },
+	},
+	{
+		Name:        "git.confirm_push",
+		Description: "Ask to push after committing",
+		Profile:     true,
+		parse:       parseBool,
+		get:         func(c *Config) interface{} { return c.Git.ConfirmPush },
+	},
+	{
+		Name:        "git.direct_commit",
+		Description: "Commit the generated message without confirmation",
+		Profile:     true,
+		parse:       parseBool,
+		get:         func(c *Config) interface{} { return c.Git.DirectCommit },
+	},
+	{
+		Name:        "git.use_ai",
+		Description: "Generate commit messages with AI",
+		Profile:     true,
+		parse:       parseBool,
+		get:         func(c *Config) interface{} { return c.Git.UseAI },
+	},
+	{
+		Name:        "git.interactive",
+		Description: "Use interactive file selection by default",
+		Profile:     true,
+		parse:       parseBool,
+		get:         func(c *Config) interface{} { return c.Git.Interactive },
+	},
+	{
+		Name:        "git.review_gate",
+		Description: "Review commits with AI and abort on findings this severe (info, low, medium, high, critical; empty disables)",
+		Profile:     true,
+		parse:       parseSeverity,
+		get:         func(c *Config) interface{} { return c.Git.ReviewGate },
+	},
+	{
+		Name:        "forge.type",
+		Description: "Pull request host (github, gitlab); detected from the remote when unset",
+		Profile:     true,
+		parse:       func(s string) (interface{}, error) { t, err := ParseForgeType(s); return t.String(), err },
+		get:         func(c *Config) interface{} { return c.Forge.Type },
+	},
+	{
+		Name:        "forge.base_url",
+		Description: "REST API base URL for GitHub Enterprise or self-hosted GitLab",
+		Profile:     true,
+		parse:       parseString,
+		get:         func(c *Config) interface{} { return c.Forge.BaseURL },
+	},
+	{
+		Name:        "forge.token",
+		Description: "API token (defaults to GH_TOKEN/GITHUB_TOKEN or GITLAB_TOKEN/GLAB_TOKEN)",
+		Secret:      true,
+		Profile:     true,
+		parse:       parseString,
+		get:         func(c *Config) interface{} { return c.Forge.Token },
+	},
+	{
+		Name:        "version",
+		Description: "Config schema version (managed by commet)",
+		parse:       parseVersion,
+		get:         func(c *Config) interface{} { return c.Version },
+	},
+	{
+		Name:        "profile",
+		Description: "Profile used when none is selected or matched",
+		parse:       parseString,
+		get:         func(c *Config) interface{} { return c.ActiveProfile },
+	},
+}...)
+
+// apiKeyKeys returns one ai.api_keys.<provider> key per supported provider
+func apiKeyKeys() []Key {
+	var result []Key
+	for _, p := range Providers() {
+		p := p
+		result = append(result, Key{
+			Name:        "ai.api_keys." + p.String(),
+			Description: fmt.Sprintf("API key for %s", p),
+			Secret:      true,
+			Profile:     true,
+			parse:       parseString,
+			get:         func(c *Config) interface{} { return c.AI.APIKeys[p.String()] },
+		})
+	}
+	return result
+}
+
+// Keys returns all known configuration keys
+func Keys() []Key {
+	return keys
+}
+
+// LookupKey finds a configuration key by name. Keys inside a profile
+// (profiles.<name>.<key>) resolve to the underlying key.
+func LookupKey(name string) (Key, error) {
+	name = strings.ToLower(name)
+	if profile, rest, ok := splitProfileKey(name); ok {
+		if rest == "match" {
+			return Key{
+				Name:        name,
+				Description: fmt.Sprintf("Remote URL patterns selecting profile %s", profile),
+				parse:       parseList,
+			}, nil
+		}
+		key, err := LookupKey(rest)
+		if err != nil {
+			return Key{}, err
+		}
+		if !key.Profile {
+			return Key{}, fmt.Errorf("key %s cannot be set in a profile", rest)
+		}
+		key.Name = name
+		return key, nil
+	}
+
+	for _, key := range keys {
+		if key.Name == name {
+			return key, nil
+		}
+	}
+
+	if suggestion := suggestKey(name); suggestion != "" {
+		return Key{}, fmt.Errorf("unknown config key: %s (did you mean %s?)", name, suggestion)
+	}
+	return Key{}, fmt.Errorf("unknown config key: %s (run 'commet config list' to see all keys)", name)
+}
+
+// Parse converts a command line value into the type stored in the config file
+func (k Key) Parse(value string) (interface{}, error) {
+	v, err := k.parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", k.Name, err)
+	}
+	return v, nil
+}
+
+// Value returns the effective value of the key for the loaded config
+func (k Key) Value(c *Config) interface{} {
+	if k.get == nil {
+		return nil
+	}
+	return k.get(c)
+}
+
+// Format renders a value for display, masking secrets
+func (k Key) Format(value interface{}) string {
+	s := fmt.Sprint(value)
+	if k.Secret && s != "" {
+		return (&AIConfig{APIKey: s}).MaskAPIKey()
+	}
+	return s
+}
+
+func splitProfileKey(name string) (string, string, bool) {
+	parts := strings.SplitN(name, ".", 3)
+	if len(parts) != 3 || parts[0] != "profiles" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func parseString(s string) (interface{}, error) {
+	return s, nil
+}
+
+func parseBool(s string) (interface{}, error) {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a boolean (use true or false)", s)
+	}
+	return b, nil
+}
+
+func parseRange(lo, hi float64) func(string) (interface{}, error) {
+	return func(s string) (interface{}, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < lo || f > hi {
+			return nil, fmt.Errorf("%q is not a number between %g and %g", s, lo, hi)
+		}
+		return f, nil
+	}
+}
+
+func parseNonNegativeInt(s string) (interface{}, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("%q is not a non-negative integer", s)
+	}
+	return n, nil
+}
+
+func parseDuration(s string) (interface{}, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("%q is not a positive duration (e.g. 30s, 2m)", s)
+	}
+	return d.String(), nil
+}
+
+func parseSeverity(s string) (interface{}, error) {
+	if strings.TrimSpace(s) == "" {
+		return "", nil
+	}
+	severity, err := ParseSeverity(s)
+	return severity.String(), err
+}
+
+func parseFallbacks(s string) (interface{}, error) {
+	fallbacks, err := ParseFallbacks(s)
+	if err != nil {
+		return nil, err
+	}
+	return fallbackSettings(fallbacks), nil
+}
+
+func parsePrices(s string) (interface{}, error) {
+	prices, err := ParsePrices(s)
+	if err != nil {
+		return nil, err
+	}
+	return priceSettings(prices), nil
+}
+
+func formatPrices(prices []Price) string {
+	entries := make([]string, 0, len(prices))
+	for _, p := range prices {
+		entries = append(entries, p.String())
+	}
+	return strings.Join(entries, ",")
+}
+
+// priceSettings converts prices to the list of maps stored in the file
+func priceSettings(prices []Price) []map[string]interface{} {
+	settings := make([]map[string]interface{}, 0, len(prices))
+	for _, p := range prices {
+		settings = append(settings, map[string]interface{}{"model": p.Model, "input": p.Input, "output": p.Output})
+	}
+	return settings
+}
+
+func formatFallbacks(fallbacks []Fallback) string {
+	entries := make([]string, 0, len(fallbacks))
+	for _, f := range fallbacks {
+		entries = append(entries, f.String())
+	}
+	return strings.Join(entries, ",")
+}
+
+// fallbackSettings converts fallbacks to the list of maps stored in the file
+func fallbackSettings(fallbacks []Fallback) []map[string]interface{} {
+	settings := make([]map[string]interface{}, 0, len(fallbacks))
+	for _, f := range fallbacks {
+		entry := map[string]interface{}{"provider": f.Provider.String()}
+		if f.Model != "" {
+			entry["model"] = f.Model
+		}
+		settings = append(settings, entry)
+	}
+	return settings
+}
+
+// FormatOptional renders an optional number, empty when unset
+func FormatOptional(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'g', -1, 64)
+}
+
+func parseVersion(s string) (interface{}, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 || v > CurrentVersion {
+		return nil, fmt.Errorf("%q is not a supported version (latest is %d)", s, CurrentVersion)
+	}
+	return v, nil
+}
+
+func parseList(s string) (interface{}, error) {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// suggestKey returns the closest known key name, if any is reasonably close
+func suggestKey(name string) string {
+	best, bestDist := "", 4
+	candidates := make([]string, 0, len(keys))
+	for _, key := range keys {
+		candidates = append(candidates, key.Name)
+	}
+	sort.Strings(candidates)
+	for _, candidate := range candidates {
+		if d := editDistance(name, candidate); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
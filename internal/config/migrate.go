@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// CurrentVersion is the config schema version written by this build.
+// Files without a version field are treated as version 1.
+const CurrentVersion = 2
+
+// migration upgrades raw settings from version `from` to `from+1`
+type migration struct {
+	from        int
+	description string
+	apply       func(settings map[string]interface{})
+}
+
+// migrations must stay ordered by `from` and cover every version below
+// CurrentVersion
+var migrations = []migration{
+	{
+		from:        1,
+		description: "move ai.api_key to per-provider ai.api_keys",
+		apply: func(settings map[string]interface{}) {
+			// Profiles without their own provider inherit the base one
+			baseProvider := ProviderOpenAI.String()
+			if ai, ok := settings["ai"].(map[string]interface{}); ok {
+				if p, _ := ai["provider"].(string); p != "" {
+					baseProvider = p
+				}
+			}
+
+			forEachAISection(settings, func(ai map[string]interface{}) {
+				key, ok := ai["api_key"]
+				if !ok {
+					return
+				}
+				delete(ai, "api_key")
+
+				provider, _ := ai["provider"].(string)
+				if provider == "" {
+					provider = baseProvider
+				}
+				keys, ok := ai["api_keys"].(map[string]interface{})
+				if !ok {
+					keys = map[string]interface{}{}
+					ai["api_keys"] = keys
+				}
+				if _, exists := keys[provider]; !exists {
+					keys[provider] = key
+				}
+			})
+		},
+	},
+}
+
+// migrateFile upgrades the config file at path to CurrentVersion, writing a
+// backup of the original first. An existing backup is never overwritten, so
+// the migration is refused until it is moved away. It reports whether the
+// file was changed.
+func migrateFile(path string) (bool, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return false, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	version := 1
+	if v.IsSet("version") {
+		version = v.GetInt("version")
+	}
+	if version > CurrentVersion {
+		return false, fmt.Errorf("config file %s has version %d, but this commet only supports up to %d; please upgrade commet", path, version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return false, nil
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("error reading config file: %w", err)
+	}
+	backupPath := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := writeBackup(backupPath, original); err != nil {
+		return false, err
+	}
+
+	settings := v.AllSettings()
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		m.apply(settings)
+		fmt.Fprintf(os.Stderr, "Migrated config to version %d: %s\n", m.from+1, m.description)
+	}
+	settings["version"] = CurrentVersion
+
+	out := viper.New()
+	out.SetConfigType("yaml")
+	if err := out.MergeConfigMap(settings); err != nil {
+		return false, fmt.Errorf("error migrating config: %w", err)
+	}
+	if err := out.WriteConfigAs(path); err != nil {
+		return false, fmt.Errorf("error writing migrated config: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Previous config saved to %s\n", backupPath)
+	return true, nil
+}
+
+// writeBackup creates path with data, failing if path already exists
+func writeBackup(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("config backup %s already exists; move it away before migrating", path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing config backup: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing config backup: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing config backup: %w", err)
+	}
+	return nil
+}
+
+// forEachAISection calls fn for the base ai section and the ai section of
+// every profile
+func forEachAISection(settings map[string]interface{}, fn func(map[string]interface{})) {
+	if ai, ok := settings["ai"].(map[string]interface{}); ok {
+		fn(ai)
+	}
+
+	profiles, ok := settings["profiles"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, p := range profiles {
+		profile, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ai, ok := profile["ai"].(map[string]interface{}); ok {
+			fn(ai)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// writeConfig writes content to a config file in a temporary directory and
+// returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readSettings returns the settings stored in the config file at path
+func readSettings(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	return v.AllSettings()
+}
+
+func TestMigrateFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		migrated bool
+		want     map[string]interface{}
+	}{
+		{
+			name:     "base section",
+			config:   "ai:\n  provider: claude\n  api_key: sk-base\n",
+			migrated: true,
+			want: map[string]interface{}{
+				"version": 2,
+				"ai":      map[string]interface{}{"provider": "claude", "api_keys": map[string]interface{}{"claude": "sk-base"}},
+			},
+		},
+		{
+			name:     "default provider",
+			config:   "ai:\n  api_key: sk-base\n",
+			migrated: true,
+			want: map[string]interface{}{
+				"version": 2,
+				"ai":      map[string]interface{}{"api_keys": map[string]interface{}{"openai": "sk-base"}},
+			},
+		},
+		{
+			name: "profile sections",
+			config: "ai:\n  provider: claude\n  api_key: sk-base\n" +
+				"profiles:\n" +
+				"  work:\n    ai:\n      provider: gemini\n      api_key: sk-work\n" +
+				"  home:\n    ai:\n      api_key: sk-home\n" +
+				"  kept:\n    ai:\n      api_key: sk-old\n      api_keys:\n        claude: sk-new\n",
+			migrated: true,
+			want: map[string]interface{}{
+				"version": 2,
+				"ai":      map[string]interface{}{"provider": "claude", "api_keys": map[string]interface{}{"claude": "sk-base"}},
+				"profiles": map[string]interface{}{
+					"work": map[string]interface{}{"ai": map[string]interface{}{"provider": "gemini", "api_keys": map[string]interface{}{"gemini": "sk-work"}}},
+					"home": map[string]interface{}{"ai": map[string]interface{}{"api_keys": map[string]interface{}{"claude": "sk-home"}}},
+					"kept": map[string]interface{}{"ai": map[string]interface{}{"api_keys": map[string]interface{}{"claude": "sk-new"}}},
+				},
+			},
+		},
+		{
+			name:   "current version",
+			config: "version: 2\nai:\n  api_keys:\n    openai: sk-base\n",
+			want: map[string]interface{}{
+				"version": 2,
+				"ai":      map[string]interface{}{"api_keys": map[string]interface{}{"openai": "sk-base"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.config)
+
+			migrated, err := migrateFile(path)
+			if err != nil {
+				t.Fatalf("migrateFile() error = %v", err)
+			}
+			if migrated != tt.migrated {
+				t.Errorf("migrateFile() = %v, want %v", migrated, tt.migrated)
+			}
+			if got := readSettings(t, path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("settings = %v, want %v", got, tt.want)
+			}
+
+			backup, err := os.ReadFile(path + ".v1.bak")
+			switch {
+			case !tt.migrated && !os.IsNotExist(err):
+				t.Errorf("backup written without a migration: %v", err)
+			case tt.migrated && string(backup) != tt.config:
+				t.Errorf("backup = %q, %v, want the original file", backup, err)
+			}
+
+			// A second run finds the file up to date
+			migrated, err = migrateFile(path)
+			if err != nil || migrated {
+				t.Errorf("second migrateFile() = %v, %v, want no change", migrated, err)
+			}
+			if got := readSettings(t, path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("settings after second run = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateFileRefuses(t *testing.T) {
+	t.Run("newer version", func(t *testing.T) {
+		config := "version: 3\nai:\n  api_key: sk-base\n"
+		path := writeConfig(t, config)
+
+		if _, err := migrateFile(path); err == nil {
+			t.Fatal("migrateFile() succeeded, want error")
+		}
+		if got, _ := os.ReadFile(path); string(got) != config {
+			t.Errorf("config = %q, want it untouched", got)
+		}
+	})
+
+	t.Run("existing backup", func(t *testing.T) {
+		config := "ai:\n  api_key: sk-base\n"
+		path := writeConfig(t, config)
+		if err := os.WriteFile(path+".v1.bak", []byte("older backup"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := migrateFile(path); err == nil {
+			t.Fatal("migrateFile() succeeded, want error")
+		}
+		if got, _ := os.ReadFile(path + ".v1.bak"); string(got) != "older backup" {
+			t.Errorf("backup = %q, want it untouched", got)
+		}
+		if got, _ := os.ReadFile(path); string(got) != config {
+			t.Errorf("config = %q, want it untouched", got)
+		}
+	})
+}
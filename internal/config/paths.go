@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const appName = "commet"
+
+// SearchPaths returns the config files commet looks for, in lookup order:
+// $XDG_CONFIG_HOME/commet/config.yaml first, then the legacy ~/.commet.yaml.
+func SearchPaths() []string {
+	var paths []string
+	if dir, err := xdgDir("XDG_CONFIG_HOME", ".config"); err == nil {
+		paths = append(paths, filepath.Join(dir, appName, "config.yaml"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".commet.yaml"))
+	}
+	return paths
+}
+
+// CacheDir returns the directory for disposable data such as cached model
+// lists, creating it if needed
+func CacheDir() (string, error) {
+	return appDir("XDG_CACHE_HOME", ".cache")
+}
+
+// StateDir returns the directory for persistent data such as history and
+// usage records, creating it if needed
+func StateDir() (string, error) {
+	return appDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+func appDir(env, fallback string) (string, error) {
+	base, err := xdgDir(env, fallback)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(base, appName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("could not create %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// xdgDir resolves an XDG base directory, ignoring relative values as the
+// spec requires
+func xdgDir(env, fallback string) (string, error) {
+	if dir := os.Getenv(env); dir != "" && filepath.IsAbs(dir) {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %w", err)
+	}
+	return filepath.Join(home, fallback), nil
+}
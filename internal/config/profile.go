@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Profile is a named set of overrides layered on top of the base ai and git
+// settings. Only the keys present in the profile section replace base values.
+type Profile struct {
+	Match []string    `mapstructure:"match" yaml:"match"`
+	AI    AIConfig    `mapstructure:"ai" yaml:"ai"`
+	Git   GitConfig   `mapstructure:"git" yaml:"git"`
+	Forge ForgeConfig `mapstructure:"forge" yaml:"forge"`
+}
+
+// selectedProfile is the profile requested on the command line
+var selectedProfile string
+
+// SelectProfile forces the named profile to be used by Load, bypassing
+// COMMET_PROFILE and remote URL matching
+func SelectProfile(name string) {
+	selectedProfile = name
+}
+
+// ProfileNames returns the configured profile names in sorted order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile overlays the named profile section onto the config. Names
+// are matched case-insensitively, since viper lowercases the keys it reads.
+func (c *Config) applyProfile(name string) error {
+	name = strings.ToLower(name)
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	sub := viper.Sub("profiles." + name)
+	if sub == nil {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	if sub.IsSet("ai") {
+		if err := sub.Sub("ai").Unmarshal(&c.AI); err != nil {
+			return fmt.Errorf("error unmarshaling profile %s: %w", name, err)
+		}
+	}
+	if sub.IsSet("git") {
+		if err := sub.Sub("git").Unmarshal(&c.Git); err != nil {
+			return fmt.Errorf("error unmarshaling profile %s: %w", name, err)
+		}
+	}
+	if sub.IsSet("forge") {
+		if err := sub.Sub("forge").Unmarshal(&c.Forge); err != nil {
+			return fmt.Errorf("error unmarshaling profile %s: %w", name, err)
+		}
+	}
+
+	c.ActiveProfile = name
+	return nil
+}
+
+// matchProfile returns the first profile (in name order) whose match patterns
+// cover one of the repository's remote URLs
+func (c *Config) matchProfile(remotes []string) string {
+	for _, name := range c.ProfileNames() {
+		for _, pattern := range c.Profiles[name].Match {
+			for _, remote := range remotes {
+				if ok, _ := path.Match(pattern, NormalizeRemoteURL(remote)); ok {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// NormalizeRemoteURL reduces ssh, scp-like and https remotes to
+// host/owner/repo so one pattern covers every form
+func NormalizeRemoteURL(url string) string {
+	url = strings.TrimSpace(url)
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	} else {
+		// scp-like syntax: git@host:owner/repo
+		url = strings.Replace(url, ":", "/", 1)
+	}
+	if i := strings.Index(url, "@"); i >= 0 && i < strings.Index(url, "/") {
+		url = url[i+1:]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+}
+
+// getRemoteURLs lists the URLs of all remotes of the repository in the
+// current directory
+func getRemoteURLs() []string {
+	cmd := exec.Command("git", "config", "--get-regexp", `^remote\..*\.url$`)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	urls := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			urls = append(urls, fields[1])
+		}
+	}
+	return urls
+}
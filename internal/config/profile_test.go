@@ -0,0 +1,51 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// loadYAML replaces the global viper settings with content for the rest of
+// the test and returns them unmarshaled
+func loadYAML(t *testing.T, content string) *Config {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := viper.Unmarshal(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	return &cfg
+}
+
+func TestApplyProfileIgnoresCase(t *testing.T) {
+	const content = `
+ai:
+  provider: openai
+profiles:
+  Work:
+    ai:
+      provider: claude
+`
+	for _, name := range []string{"Work", "work", "WORK"} {
+		cfg := loadYAML(t, content)
+		if err := cfg.applyProfile(name); err != nil {
+			t.Fatalf("applyProfile(%q) error = %v", name, err)
+		}
+		if cfg.AI.Provider != ProviderClaude || cfg.ActiveProfile != "work" {
+			t.Errorf("applyProfile(%q): provider = %s, profile = %q, want claude from work", name, cfg.AI.Provider, cfg.ActiveProfile)
+		}
+	}
+
+	cfg := loadYAML(t, content)
+	if err := cfg.applyProfile("home"); err == nil {
+		t.Error("applyProfile(\"home\") succeeded, want an unknown profile error")
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks code review findings
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Severities lists the severities from least to most severe
+func Severities() []Severity {
+	return []Severity{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+}
+
+func (s Severity) String() string {
+	return string(s)
+}
+
+// Rank orders severities; unknown values rank below info
+func (s Severity) Rank() int {
+	for i, severity := range Severities() {
+		if s == severity {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as severe as threshold
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.Rank() >= threshold.Rank()
+}
+
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if severity.Rank() == 0 {
+		return "", fmt.Errorf("invalid severity: %s (valid options: info, low, medium, high, critical)", s)
+	}
+	return severity, nil
+}
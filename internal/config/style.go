@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Style string
+
+const (
+	StyleConventional Style = "conventional"
+	StyleSimple       Style = "simple"
+	StyleDetailed     Style = "detailed"
+)
+
+func (s Style) String() string {
+	return string(s)
+}
+
+func (s Style) IsValid() bool {
+	switch s {
+	case StyleConventional, StyleSimple, StyleDetailed:
+		return true
+	default:
+		return false
+	}
+}
+
+func ParseStyle(s string) (Style, error) {
+	style := Style(strings.ToLower(s))
+	if !style.IsValid() {
+		return "", fmt.Errorf("invalid style: %s (valid options: conventional, simple, detailed)", s)
+	}
+	return style, nil
+}
@@ -0,0 +1,179 @@
+// Package forge opens and updates pull requests on GitHub and merge requests
+// on GitLab through their REST APIs.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// PullRequest is a pull request or merge request on a forge
+type PullRequest struct {
+	Number int
+	URL    string
+	Title  string
+	Body   string
+}
+
+// Client creates and updates pull requests for one repository
+type Client interface {
+	// FindPullRequest returns the open pull request from head into base, or
+	// nil when there is none
+	FindPullRequest(ctx context.Context, head, base string) (*PullRequest, error)
+	CreatePullRequest(ctx context.Context, head, base, title, body string, draft bool) (*PullRequest, error)
+	UpdatePullRequest(ctx context.Context, number int, title, body string) (*PullRequest, error)
+}
+
+// Repo identifies a repository on a forge host
+type Repo struct {
+	Host string
+	// Path is owner/name; GitLab paths may contain nested groups
+	Path string
+}
+
+// ParseRemote extracts the host and repository path from an ssh, scp-like or
+// https remote URL
+func ParseRemote(url string) (Repo, error) {
+	host, path, ok := strings.Cut(config.NormalizeRemoteURL(url), "/")
+	path = strings.Trim(path, "/")
+	if !ok || host == "" || !strings.Contains(path, "/") {
+		return Repo{}, fmt.Errorf("cannot determine repository from remote URL %q", url)
+	}
+	// Drop an explicit port from ssh://host:22/owner/repo style URLs
+	if h, _, found := strings.Cut(host, ":"); found {
+		host = h
+	}
+	return Repo{Host: host, Path: path}, nil
+}
+
+// New returns a client for repo. The forge type and API base URL come from
+// cfg or are derived from the remote host.
+func New(cfg config.ForgeConfig, repo Repo, httpClient *http.Client) (Client, error) {
+	forgeType := cfg.Type
+	if forgeType == "" {
+		forgeType = config.ForgeGitHub
+		if strings.Contains(repo.Host, "gitlab") {
+			forgeType = config.ForgeGitLab
+		}
+	}
+
+	token := cfg.ResolveToken(forgeType)
+	if token == "" {
+		return nil, fmt.Errorf("no %s token configured; set forge.token or %s", forgeType, strings.Join(config.TokenEnv(forgeType), "/"))
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	api := &apiClient{http: httpClient, baseURL: strings.TrimSuffix(cfg.BaseURL, "/")}
+	switch forgeType {
+	case config.ForgeGitHub:
+		if api.baseURL == "" {
+			api.baseURL = "https://api.github.com"
+			if repo.Host != "github.com" {
+				// GitHub Enterprise Server
+				api.baseURL = "https://" + repo.Host + "/api/v3"
+			}
+		}
+		api.headers = map[string]string{
+			"Authorization":        "Bearer " + token,
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+		}
+		return &github{api: api, repo: repo}, nil
+	case config.ForgeGitLab:
+		if api.baseURL == "" {
+			api.baseURL = "https://" + repo.Host + "/api/v4"
+		}
+		api.headers = map[string]string{"PRIVATE-TOKEN": token}
+		return &gitlab{api: api, repo: repo}, nil
+	default:
+		return nil, fmt.Errorf("unsupported forge: %s", forgeType)
+	}
+}
+
+// apiClient performs authenticated JSON requests against a REST API
+type apiClient struct {
+	http    *http.Client
+	baseURL string
+	headers map[string]string
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", method, path, apiError(resp.Status, data))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
+
+// apiError extracts the message GitHub and GitLab put in error responses
+func apiError(status string, data []byte) string {
+	var body struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return status
+	}
+
+	parts := []string{status}
+	if body.Message != nil {
+		parts = append(parts, fmt.Sprint(body.Message))
+	}
+	if body.Error != "" {
+		parts = append(parts, body.Error)
+	}
+	for _, e := range body.Errors {
+		if e.Message != "" {
+			parts = append(parts, e.Message)
+		}
+	}
+	return strings.Join(parts, ": ")
+}
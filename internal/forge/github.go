@@ -0,0 +1,74 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// github implements Client with the GitHub REST API
+type github struct {
+	api  *apiClient
+	repo Repo
+}
+
+type githubPull struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+}
+
+func (p githubPull) toPullRequest() *PullRequest {
+	return &PullRequest{Number: p.Number, URL: p.HTMLURL, Title: p.Title, Body: p.Body}
+}
+
+func (g *github) FindPullRequest(ctx context.Context, head, base string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(g.repo.Path, "/")
+	query := url.Values{
+		"head":  {owner + ":" + head},
+		"base":  {base},
+		"state": {"open"},
+	}
+
+	var pulls []githubPull
+	if err := g.api.do(ctx, http.MethodGet, g.path("/pulls?"+query.Encode()), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return pulls[0].toPullRequest(), nil
+}
+
+func (g *github) CreatePullRequest(ctx context.Context, head, base, title, body string, draft bool) (*PullRequest, error) {
+	in := map[string]interface{}{
+		"head":  head,
+		"base":  base,
+		"title": title,
+		"body":  body,
+		"draft": draft,
+	}
+
+	var pull githubPull
+	if err := g.api.do(ctx, http.MethodPost, g.path("/pulls"), in, &pull); err != nil {
+		return nil, err
+	}
+	return pull.toPullRequest(), nil
+}
+
+func (g *github) UpdatePullRequest(ctx context.Context, number int, title, body string) (*PullRequest, error) {
+	in := map[string]interface{}{"title": title, "body": body}
+
+	var pull githubPull
+	if err := g.api.do(ctx, http.MethodPatch, g.path(fmt.Sprintf("/pulls/%d", number)), in, &pull); err != nil {
+		return nil, err
+	}
+	return pull.toPullRequest(), nil
+}
+
+func (g *github) path(suffix string) string {
+	return "/repos/" + g.repo.Path + suffix
+}
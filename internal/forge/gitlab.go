@@ -0,0 +1,75 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// gitlab implements Client with the GitLab REST API
+type gitlab struct {
+	api  *apiClient
+	repo Repo
+}
+
+type gitlabMergeRequest struct {
+	IID         int    `json:"iid"`
+	WebURL      string `json:"web_url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (mr gitlabMergeRequest) toPullRequest() *PullRequest {
+	return &PullRequest{Number: mr.IID, URL: mr.WebURL, Title: mr.Title, Body: mr.Description}
+}
+
+func (g *gitlab) FindPullRequest(ctx context.Context, head, base string) (*PullRequest, error) {
+	query := url.Values{
+		"source_branch": {head},
+		"target_branch": {base},
+		"state":         {"opened"},
+	}
+
+	var mrs []gitlabMergeRequest
+	if err := g.api.do(ctx, http.MethodGet, g.path("/merge_requests?"+query.Encode()), nil, &mrs); err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return mrs[0].toPullRequest(), nil
+}
+
+func (g *gitlab) CreatePullRequest(ctx context.Context, head, base, title, body string, draft bool) (*PullRequest, error) {
+	if draft {
+		title = "Draft: " + title
+	}
+	in := map[string]interface{}{
+		"source_branch": head,
+		"target_branch": base,
+		"title":         title,
+		"description":   body,
+	}
+
+	var mr gitlabMergeRequest
+	if err := g.api.do(ctx, http.MethodPost, g.path("/merge_requests"), in, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPullRequest(), nil
+}
+
+func (g *gitlab) UpdatePullRequest(ctx context.Context, number int, title, body string) (*PullRequest, error) {
+	in := map[string]interface{}{"title": title, "description": body}
+
+	var mr gitlabMergeRequest
+	if err := g.api.do(ctx, http.MethodPut, g.path(fmt.Sprintf("/merge_requests/%d", number)), in, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPullRequest(), nil
+}
+
+// path addresses the project by its URL-encoded path, e.g. group%2Frepo
+func (g *gitlab) path(suffix string) string {
+	return "/projects/" + url.PathEscape(g.repo.Path) + suffix
+}
@@ -0,0 +1,171 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commit is a commit in a range of history
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
+// Message returns the full commit message
+func (c Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// DefaultBaseBranch guesses the branch pull requests on remote are merged
+// into: the remote's HEAD when known, then its main or master, then the local
+// main or master
+func DefaultBaseBranch(remote string) (string, error) {
+	if output, err := command("symbolic-ref", "--quiet", "--short", "refs/remotes/"+remote+"/HEAD").Output(); err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	for _, candidate := range []string{remote + "/main", remote + "/master", "main", "master"} {
+		if command("rev-parse", "--verify", "--quiet", candidate).Run() == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine the base branch; pass it explicitly")
+}
+
+// MergeBase returns the best common ancestor of two revisions
+func MergeBase(a, b string) (string, error) {
+	output, err := command("merge-base", a, b).Output()
+	if err != nil {
+		return "", fmt.Errorf("no common ancestor of %s and %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SameTree reports whether two revisions record the same tree
+func SameTree(a, b string) (bool, error) {
+	output, err := command("rev-parse", a+"^{tree}", b+"^{tree}").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read the trees of %s and %s: %w", a, b, err)
+	}
+	trees := strings.Fields(string(output))
+	return len(trees) == 2 && trees[0] == trees[1], nil
+}
+
+// GetRangeDiff returns the diff between two revisions
+func GetRangeDiff(from, to string) (string, error) {
+	output, err := command("diff", from, to).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s..%s: %w", from, to, err)
+	}
+	return string(output), nil
+}
+
+// GetCommits returns the commits reachable from to but not from from, oldest
+// first. An empty from lists the whole history of to.
+func GetCommits(from, to string) ([]Commit, error) {
+	return getCommits(from, to)
+}
+
+// GetFirstParentCommits is GetCommits along the first-parent chain only, so a
+// merged branch shows up as its merge commit without the commits it brought in
+func GetFirstParentCommits(from, to string) ([]Commit, error) {
+	return getCommits(from, to, "--first-parent")
+}
+
+func getCommits(from, to string, options ...string) ([]Commit, error) {
+	rev := to
+	if from != "" {
+		rev = from + ".." + to
+	}
+
+	// Unit and record separators keep multi-line bodies intact
+	args := append([]string{"log", "--reverse", "--format=%H%x1f%s%x1f%b%x1e"}, options...)
+	output, err := command(append(args, rev)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits %s: %w", rev, err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Subject: fields[1],
+			Body:    strings.TrimSpace(fields[2]),
+		})
+	}
+	return commits, nil
+}
+
+// GetRemoteURL returns the fetch URL of a remote
+func GetRemoteURL(remote string) (string, error) {
+	output, err := command("remote", "get-url", remote).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// HasUpstream reports whether the current branch tracks a remote branch
+func HasUpstream() bool {
+	return command("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}").Run() == nil
+}
+
+// PushBranch pushes branch to remote, also making it the upstream when
+// setUpstream is true
+func PushBranch(remote, branch string, setUpstream bool) error {
+	args := []string{"push", remote, branch}
+	if setUpstream {
+		args = []string{"push", "--set-upstream", remote, branch}
+	}
+	cmd := command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %s", branch, remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// GetLatestTag returns the most recent tag reachable from rev
+func GetLatestTag(rev string) (string, error) {
+	output, err := command("describe", "--tags", "--abbrev=0", rev).Output()
+	if err != nil {
+		return "", fmt.Errorf("no tag reachable from %s", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetTags returns the tags reachable from rev
+func GetTags(rev string) ([]string, error) {
+	output, err := command("tag", "--merged", rev).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// CreateTag creates an annotated tag on HEAD. The message is kept verbatim
+// so Markdown headings survive.
+func CreateTag(name, message string) error {
+	cmd := command("tag", "--annotate", "--cleanup=verbatim", "--file=-", name)
+	cmd.Stdin = strings.NewReader(message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create tag %s: %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PushTag pushes a single tag to remote
+func PushTag(remote, name string) error {
+	cmd := command("push", remote, "refs/tags/"+name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to push tag %s to %s: %s", name, remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
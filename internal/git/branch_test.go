@@ -0,0 +1,36 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/bitcs/commet/internal/testutil"
+)
+
+func TestDefaultBaseBranch(t *testing.T) {
+	testutil.NewRepo(t)
+	testutil.Git(t, "checkout", "-q", "-b", "trunk")
+	testutil.WriteFile(t, "file.txt", "content\n")
+	testutil.Git(t, "add", "file.txt")
+	testutil.Git(t, "commit", "-q", "-m", "initial")
+
+	if got, err := DefaultBaseBranch("origin"); err == nil {
+		t.Errorf("DefaultBaseBranch() = %s without any base branch, want error", got)
+	}
+
+	testutil.Git(t, "update-ref", "refs/remotes/origin/main", "HEAD")
+	testutil.Git(t, "update-ref", "refs/remotes/upstream/master", "HEAD")
+	testutil.Git(t, "update-ref", "refs/remotes/upstream/develop", "HEAD")
+
+	tests := map[string]string{"origin": "origin/main", "upstream": "upstream/master"}
+	for remote, want := range tests {
+		if got, err := DefaultBaseBranch(remote); err != nil || got != want {
+			t.Errorf("DefaultBaseBranch(%q) = %s, %v, want %s", remote, got, err, want)
+		}
+	}
+
+	// The remote's HEAD wins over the usual branch names
+	testutil.Git(t, "symbolic-ref", "refs/remotes/upstream/HEAD", "refs/remotes/upstream/develop")
+	if got, err := DefaultBaseBranch("upstream"); err != nil || got != "upstream/develop" {
+		t.Errorf("DefaultBaseBranch(\"upstream\") = %s, %v, want upstream/develop", got, err)
+	}
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+var topLevelCache struct {
+	sync.Mutex
+	dir string
+	top string
+	err error
+}
+
+// TopLevel returns the absolute path of the working tree root of the
+// repository containing the current directory. The result is cached until
+// the current directory changes.
+func TopLevel() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	c := &topLevelCache
+	c.Lock()
+	defer c.Unlock()
+	if c.dir == cwd {
+		return c.top, c.err
+	}
+
+	c.dir, c.top, c.err = cwd, "", nil
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		c.err = fmt.Errorf("not inside a git working tree: %w", err)
+	} else {
+		c.top = strings.TrimSpace(string(output))
+	}
+	return c.top, c.err
+}
+
+// prefix returns the path of the current directory relative to the repository
+// root, with a trailing slash, or "" at the root
+func prefix() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return "", fmt.Errorf("not inside a git working tree: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// command returns a git command that runs at the repository top-level, so the
+// paths it prints and accepts are relative to the repository root no matter
+// which subdirectory commet was started from
+func command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	if top, err := TopLevel(); err == nil {
+		cmd.Dir = top
+	}
+	return cmd
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is the unified diff of a single file split into hunks
+type FileDiff struct {
+	// Header holds the lines before the first hunk (diff --git, index, ---, +++)
+	Header []string
+	Hunks  []Hunk
+}
+
+// Hunk is one @@ section of a unified diff
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Section is the text after the closing @@, usually a function name
+	Section string
+	// Lines are the context, added and removed lines of the hunk
+	Lines []string
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
+
+// Header returns the @@ line of the hunk
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@%s", h.OldStart, h.OldLines, h.NewStart, h.NewLines, h.Section)
+}
+
+// ParseFileDiff splits the diff of a single file into its header and hunks
+func ParseFileDiff(diff string) (*FileDiff, error) {
+	fd := &FileDiff{}
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+
+	for _, line := range lines {
+		if m := hunkHeaderPattern.FindStringSubmatch(line); m != nil {
+			fd.Hunks = append(fd.Hunks, Hunk{
+				OldStart: atoi(m[1], 0),
+				OldLines: atoi(m[2], 1),
+				NewStart: atoi(m[3], 0),
+				NewLines: atoi(m[4], 1),
+				Section:  m[5],
+			})
+			continue
+		}
+		if len(fd.Hunks) == 0 {
+			if line != "" {
+				fd.Header = append(fd.Header, line)
+			}
+			continue
+		}
+		last := &fd.Hunks[len(fd.Hunks)-1]
+		last.Lines = append(last.Lines, line)
+	}
+
+	if len(fd.Hunks) > 0 && len(fd.Header) == 0 {
+		return nil, fmt.Errorf("diff has hunks but no file header")
+	}
+	return fd, nil
+}
+
+// Patch builds a patch containing only the hunks at the given indexes. The
+// new-file line numbers are recomputed so the patch applies on its own.
+func (d *FileDiff) Patch(indexes []int) string {
+	include := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		include[i] = true
+	}
+
+	var b strings.Builder
+	for _, line := range d.Header {
+		b.WriteString(line + "\n")
+	}
+
+	offset := 0
+	for i, h := range d.Hunks {
+		if !include[i] {
+			continue
+		}
+		h.NewStart = h.OldStart + offset
+		if h.NewLines == 0 && h.OldLines > 0 {
+			// Pure deletions anchor on the line before the hunk
+			h.NewStart--
+		} else if h.OldLines == 0 {
+			// Pure additions are reported after the anchor line
+			h.NewStart++
+		}
+		offset += h.NewLines - h.OldLines
+
+		b.WriteString(h.Header() + "\n")
+		for _, line := range h.Lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// GetFileDiff returns the staged or unstaged diff of a single file
+func GetFileDiff(filename string, staged bool) (string, error) {
+	args := []string{"diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	args = append(args, "--", filename)
+
+	output, err := command(args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff for %s: %w", filename, err)
+	}
+	return string(output), nil
+}
+
+// ApplyPatchToIndex stages the changes in patch without touching the working
+// tree, like choosing hunks in git add -p
+func ApplyPatchToIndex(patch string) error {
+	cmd := command("apply", "--cached", "--whitespace=nowarn", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git apply failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func atoi(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
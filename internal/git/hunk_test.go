@@ -0,0 +1,141 @@
+package git
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bitcs/commet/internal/testutil"
+)
+
+const twoHunkDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@ package main
+ line 1
++added
+ line 2
+ line 3
+@@ -10 +10,0 @@ func main() {
+-line 10
+`
+
+func TestParseFileDiff(t *testing.T) {
+	fd, err := ParseFileDiff(twoHunkDiff)
+	if err != nil {
+		t.Fatalf("ParseFileDiff() error = %v", err)
+	}
+
+	wantHeader := []string{"diff --git a/main.go b/main.go", "index 1111111..2222222 100644", "--- a/main.go", "+++ b/main.go"}
+	if !reflect.DeepEqual(fd.Header, wantHeader) {
+		t.Errorf("Header = %q, want %q", fd.Header, wantHeader)
+	}
+
+	want := []Hunk{
+		{OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 4, Section: " package main", Lines: []string{" line 1", "+added", " line 2", " line 3"}},
+		{OldStart: 10, OldLines: 1, NewStart: 10, NewLines: 0, Section: " func main() {", Lines: []string{"-line 10"}},
+	}
+	if !reflect.DeepEqual(fd.Hunks, want) {
+		t.Errorf("Hunks = %+v, want %+v", fd.Hunks, want)
+	}
+	if got := fd.Hunks[1].Header(); got != "@@ -10,1 +10,0 @@ func main() {" {
+		t.Errorf("Header() = %q", got)
+	}
+}
+
+func TestParseFileDiffEdgeCases(t *testing.T) {
+	fd, err := ParseFileDiff("")
+	if err != nil || len(fd.Hunks) != 0 {
+		t.Errorf("ParseFileDiff(\"\") = %+v, %v, want no hunks", fd, err)
+	}
+
+	if _, err := ParseFileDiff("@@ -1 +1 @@\n-a\n+b\n"); err == nil {
+		t.Error("ParseFileDiff() without a file header succeeded, want error")
+	}
+}
+
+func TestPatchRecomputesNewStart(t *testing.T) {
+	fd, err := ParseFileDiff(twoHunkDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the first hunk's added line the deletion starts one line earlier
+	patch := fd.Patch([]int{1})
+	if !strings.Contains(patch, "@@ -10,1 +9,0 @@") {
+		t.Errorf("Patch([1]) = %q, want the deletion anchored at line 9", patch)
+	}
+	if strings.Contains(patch, "+added") {
+		t.Errorf("Patch([1]) includes an unselected hunk: %q", patch)
+	}
+	if !strings.HasPrefix(patch, "diff --git a/main.go b/main.go\n") {
+		t.Errorf("Patch([1]) lost the file header: %q", patch)
+	}
+
+	if got := fd.Patch([]int{0, 1}); !strings.Contains(got, "@@ -10,1 +10,0 @@") {
+		t.Errorf("Patch([0 1]) = %q, want the original line numbers", got)
+	}
+}
+
+// TestPatchApplies stages every subset of the hunks of a real diff and checks
+// the index ends up with exactly the chosen changes
+func TestPatchApplies(t *testing.T) {
+	testutil.NewRepo(t)
+
+	var original []string
+	for i := 1; i <= 30; i++ {
+		original = append(original, "line "+strconv.Itoa(i))
+	}
+	testutil.WriteFile(t, "file.txt", strings.Join(original, "\n")+"\n")
+	testutil.Git(t, "add", "file.txt")
+	testutil.Git(t, "commit", "-q", "-m", "initial")
+
+	// An addition at the top, a modification in the middle and the last two
+	// lines removed, far enough apart to give three hunks
+	changed := append([]string{"new first"}, original...)
+	changed[15] = "modified"
+	changed = changed[:len(changed)-2]
+	testutil.WriteFile(t, "file.txt", strings.Join(changed, "\n")+"\n")
+
+	diff, err := GetFileDiff("file.txt", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, err := ParseFileDiff(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fd.Hunks) != 3 {
+		t.Fatalf("got %d hunks, want 3", len(fd.Hunks))
+	}
+
+	for mask := 1; mask < 8; mask++ {
+		var indexes []int
+		for i := 0; i < 3; i++ {
+			if mask&(1<<i) != 0 {
+				indexes = append(indexes, i)
+			}
+		}
+
+		testutil.Git(t, "reset", "-q")
+		if err := ApplyPatchToIndex(fd.Patch(indexes)); err != nil {
+			t.Fatalf("hunks %v: %v", indexes, err)
+		}
+
+		want := append([]string(nil), original...)
+		if mask&4 != 0 {
+			want = want[:len(want)-2]
+		}
+		if mask&2 != 0 {
+			want[14] = "modified"
+		}
+		if mask&1 != 0 {
+			want = append([]string{"new first"}, want...)
+		}
+		if got := testutil.Git(t, "show", ":file.txt"); got != strings.Join(want, "\n") {
+			t.Errorf("hunks %v: index has\n%s\nwant\n%s", indexes, got, strings.Join(want, "\n"))
+		}
+	}
+}
@@ -2,62 +2,82 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/bitcs/commet/internal/config"
 )
 
 func GetDiff(cfg *config.Config) (string, error) {
+	if !cfg.Git.AutoStage {
+		return GetPendingDiff()
+	}
+
 	// First try staged changes
-	cmd := exec.Command("git", "diff", "--cached")
+	cmd := command("diff", "--cached")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get staged changes: %w", err)
 	}
 
-	// If no staged changes and auto-stage is enabled, stage all changes
+	// If no staged changes, stage all changes
 	if len(output) == 0 {
-		if cfg.Git.AutoStage {
-			if err := StageAllChanges(); err != nil {
-				return "", fmt.Errorf("failed to auto-stage changes: %w", err)
-			}
-			// Get staged changes after auto-staging
-			cmd = exec.Command("git", "diff", "--cached")
-			output, err = cmd.Output()
-			if err != nil {
-				return "", fmt.Errorf("failed to get staged changes after auto-staging: %w", err)
-			}
-		} else {
-			// Get working directory changes
-			cmd = exec.Command("git", "diff")
-			output, err = cmd.Output()
-			if err != nil {
-				return "", fmt.Errorf("failed to get working directory changes: %w", err)
-			}
+		if err := StageAllChanges(); err != nil {
+			return "", fmt.Errorf("failed to auto-stage changes: %w", err)
+		}
+		// Get staged changes after auto-staging
+		cmd = command("diff", "--cached")
+		output, err = cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to get staged changes after auto-staging: %w", err)
 		}
 	}
 
 	return string(output), nil
 }
 
+// GetPendingDiff returns the staged changes, or the working directory
+// changes when nothing is staged. It never stages anything.
+func GetPendingDiff() (string, error) {
+	output, err := command("diff", "--cached").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged changes: %w", err)
+	}
+	if len(output) == 0 {
+		if output, err = command("diff").Output(); err != nil {
+			return "", fmt.Errorf("failed to get working directory changes: %w", err)
+		}
+	}
+	return string(output), nil
+}
+
+// StageAllChanges stages every change under the directory commet was started
+// from, as `git add .` run there would
 func StageAllChanges() error {
-	cmd := exec.Command("git", "add", ".")
+	dir, err := prefix()
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		dir = "."
+	}
+	cmd := command("add", "--", dir)
 	return cmd.Run()
 }
 
 func CreateCommit(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
+	cmd := command("commit", "-m", message)
 	return cmd.Run()
 }
 
 func PushChanges() error {
-	cmd := exec.Command("git", "push")
+	cmd := command("push")
 	return cmd.Run()
 }
 
 func GetStatus() (string, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
+	cmd := command("status", "--porcelain")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get git status: %w", err)
@@ -66,18 +86,26 @@ func GetStatus() (string, error) {
 }
 
 func IsRepository() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	err := cmd.Run()
+	_, err := TopLevel()
 	return err == nil
 }
 
+// GetRepositoryName returns the name of the repository's top-level directory
+func GetRepositoryName() (string, error) {
+	top, err := TopLevel()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(top), nil
+}
+
 func GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
+	cmd := command("branch", "--show-current")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
-	return string(output), nil
+	return strings.TrimSpace(string(output)), nil
 }
 
 func StageFiles(files []string) error {
@@ -85,8 +113,8 @@ func StageFiles(files []string) error {
 		return nil
 	}
 
-	args := append([]string{"add"}, files...)
-	cmd := exec.Command("git", args...)
+	args := append([]string{"add", "--"}, files...)
+	cmd := command(args...)
 	return cmd.Run()
 }
 
@@ -96,10 +124,78 @@ func UnstageFiles(files []string) error {
 	}
 
 	args := append([]string{"reset", "HEAD", "--"}, files...)
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	return cmd.Run()
 }
 
+// ResetIndex unstages all changes while keeping the working tree
+func ResetIndex() error {
+	cmd := command("reset", "-q")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset index: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// WriteTree records the index as a tree object and returns its hash
+func WriteTree() (string, error) {
+	output, err := command("write-tree").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot the index: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ReadTree replaces the index with the contents of a tree
+func ReadTree(tree string) error {
+	if output, err := command("read-tree", tree).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore the index: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// StagePaths stages the additions, modifications and deletions of paths
+func StagePaths(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	args := append([]string{"add", "-A", "--"}, paths...)
+	cmd := command(args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stage %v: %s", paths, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// GetDiffAgainstHead returns the staged and unstaged changes of filename
+// relative to HEAD, without rename detection
+func GetDiffAgainstHead(filename string) (string, error) {
+	base := "HEAD"
+	if _, err := RevParse("HEAD"); err != nil {
+		// On an unborn branch every change is new
+		if base, err = emptyTree(); err != nil {
+			return "", err
+		}
+	}
+
+	output, err := command("diff", base, "--no-renames", "--", filename).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff for %s: %w", filename, err)
+	}
+	return string(output), nil
+}
+
+// emptyTree returns the hash of the tree with no entries in the repository's
+// object format
+func emptyTree() (string, error) {
+	output, err := command("hash-object", "-t", "tree", "--stdin").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the empty tree: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 func GetDiffForFiles(files []string, staged bool) (string, error) {
 	args := []string{"diff"}
 
@@ -112,7 +208,7 @@ func GetDiffForFiles(files []string, staged bool) (string, error) {
 		args = append(args, files...)
 	}
 
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get diff for files: %w", err)
@@ -122,79 +218,47 @@ func GetDiffForFiles(files []string, staged bool) (string, error) {
 }
 
 func GetUnstagedFiles() ([]string, error) {
-	cmd := exec.Command("git", "diff", "--name-only")
-	output, err := cmd.Output()
+	files, err := statusPaths(StatusEntry.IsUnstaged)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get unstaged files: %w", err)
 	}
-
-	files := []string{}
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line != "" {
-			files = append(files, line)
-		}
-	}
-
 	return files, nil
 }
 
 func GetStagedFiles() ([]string, error) {
-	cmd := exec.Command("git", "diff", "--cached", "--name-only")
-	output, err := cmd.Output()
+	files, err := statusPaths(StatusEntry.IsStaged)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get staged files: %w", err)
 	}
-
-	files := []string{}
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line != "" {
-			files = append(files, line)
-		}
-	}
-
 	return files, nil
 }
 
-// GetFileStatus returns the git status for a single file (M, A, D, etc.)
-func GetFileStatus(filename string) (string, error) {
-	cmd := exec.Command("git", "status", "--porcelain", filename)
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get file status: %w", err)
-	}
-	
-	status := strings.TrimSpace(string(output))
-	if len(status) >= 2 {
-		return status[:2], nil
-	}
-	return "??", nil // untracked
-}
-
 // GetSingleFileDiff returns the diff for a single file
 func GetSingleFileDiff(filename string) (string, error) {
 	// Try staged diff first
-	cmd := exec.Command("git", "diff", "--cached", filename)
+	cmd := command("diff", "--cached", "--", filename)
 	output, err := cmd.Output()
 	if err == nil && len(output) > 0 {
 		return string(output), nil
 	}
 	
 	// Try unstaged diff
-	cmd = exec.Command("git", "diff", filename)
+	cmd = command("diff", "--", filename)
 	output, err = cmd.Output()
 	if err == nil && len(output) > 0 {
 		return string(output), nil
 	}
 	
 	// For untracked files, show the entire file content as additions
-	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard", filename)
+	cmd = command("ls-files", "--others", "--exclude-standard", "--", filename)
 	output, err = cmd.Output()
 	if err == nil && len(output) > 0 {
 		// This is an untracked file, show it as all additions
-		cmd = exec.Command("cat", filename)
-		content, err := cmd.Output()
+		top, err := TopLevel()
+		if err != nil {
+			return "", err
+		}
+		content, err := os.ReadFile(filepath.Join(top, filename))
 		if err != nil {
 			return "", fmt.Errorf("failed to read untracked file: %w", err)
 		}
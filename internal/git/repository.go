@@ -0,0 +1,56 @@
+package git
+
+import "github.com/bitcs/commet/internal/config"
+
+// Repository is the set of git operations the commands depend on. CLI
+// implements it with the git binary; tests and other front ends can provide
+// their own implementation.
+type Repository interface {
+	GetDiff(cfg *config.Config) (string, error)
+	// GetPendingDiff returns the staged diff, or the unstaged one when nothing
+	// is staged, without ever staging anything
+	GetPendingDiff() (string, error)
+	GetDiffForFiles(files []string, staged bool) (string, error)
+	// Status reads the staged, unstaged and untracked paths in one git call
+	Status() ([]StatusEntry, error)
+	GetStagedFiles() ([]string, error)
+	// GetSingleFileDiff returns the staged diff of a file, falling back to its
+	// unstaged changes or its contents when untracked
+	GetSingleFileDiff(file string) (string, error)
+	GetFileDiff(file string, staged bool) (string, error)
+	StageFiles(files []string) error
+	UnstageFiles(files []string) error
+	// StagePatch applies a patch to the index only
+	StagePatch(patch string) error
+	CreateCommit(message string) error
+	PushChanges() error
+	GetRepositoryName() (string, error)
+}
+
+// CLI is the Repository backed by the git command line
+type CLI struct{}
+
+var _ Repository = CLI{}
+
+func (CLI) GetDiff(cfg *config.Config) (string, error) { return GetDiff(cfg) }
+
+func (CLI) GetPendingDiff() (string, error) { return GetPendingDiff() }
+
+func (CLI) GetDiffForFiles(files []string, staged bool) (string, error) {
+	return GetDiffForFiles(files, staged)
+}
+
+func (CLI) GetSingleFileDiff(file string) (string, error) { return GetSingleFileDiff(file) }
+
+func (CLI) GetFileDiff(file string, staged bool) (string, error) {
+	return GetFileDiff(file, staged)
+}
+
+func (CLI) Status() ([]StatusEntry, error)     { return Status() }
+func (CLI) GetStagedFiles() ([]string, error)  { return GetStagedFiles() }
+func (CLI) StageFiles(files []string) error    { return StageFiles(files) }
+func (CLI) UnstageFiles(files []string) error  { return UnstageFiles(files) }
+func (CLI) StagePatch(patch string) error      { return ApplyPatchToIndex(patch) }
+func (CLI) CreateCommit(message string) error  { return CreateCommit(message) }
+func (CLI) PushChanges() error                 { return PushChanges() }
+func (CLI) GetRepositoryName() (string, error) { return GetRepositoryName() }
@@ -0,0 +1,194 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RevParse resolves a revision to its full commit hash
+func RevParse(rev string) (string, error) {
+	output, err := command("rev-parse", "--verify", "--quiet", rev+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetCommitDiff returns the patch a commit introduced
+func GetCommitDiff(hash string) (string, error) {
+	output, err := command("show", "--format=", "--patch", hash, "--").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff of %s: %w", hash, err)
+	}
+	return string(output), nil
+}
+
+// IsPushed reports whether a commit is reachable from a remote-tracking branch
+func IsPushed(hash string) (bool, error) {
+	output, err := command("for-each-ref", "--contains", hash, "--format=%(refname)", "refs/remotes").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether %s is pushed: %w", hash, err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// FindMerge returns the oldest merge commit between from and HEAD, or an
+// empty string when that history is linear
+func FindMerge(from string) (string, error) {
+	output, err := command("rev-list", "--merges", "--reverse", headRange(from)).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list merges: %w", err)
+	}
+	merge, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return merge, nil
+}
+
+// headRange returns the revision range of the commits after from up to HEAD,
+// or all of HEAD's history when from is empty
+func headRange(from string) string {
+	if from == "" {
+		return "HEAD"
+	}
+	return from + "..HEAD"
+}
+
+// RewriteMessages replaces the messages of commits between from and HEAD,
+// keyed by full hash, the way a rebase that only rewords would: trees and
+// authors are kept and every later commit is recreated on top of the
+// reworded one. The current branch is then moved to the new history, which
+// leaves the index and working tree untouched. Ranges containing merges are
+// refused.
+func RewriteMessages(from string, messages map[string]string) (int, error) {
+	output, err := command("rev-list", "--reverse", "--parents", headRange(from)).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	oldHead, err := RevParse("HEAD")
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		parent    string
+		rewritten bool
+		count     int
+	)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		hash, parents := fields[0], fields[1:]
+		if len(parents) > 1 {
+			return 0, fmt.Errorf("commit %s is a merge; only linear history can be reworded", hash[:7])
+		}
+
+		message, reword := messages[hash]
+		if !reword && !rewritten {
+			// Nothing changed so far, so the commit can stay as it is
+			parent = hash
+			continue
+		}
+		if !reword {
+			if message, err = rawMessage(hash); err != nil {
+				return 0, err
+			}
+		}
+		if !rewritten && len(parents) == 1 {
+			parent = parents[0]
+		}
+
+		newHash, err := recommit(hash, parent, message)
+		if err != nil {
+			return 0, err
+		}
+		parent = newHash
+		rewritten = true
+		if reword {
+			count++
+		}
+	}
+
+	if !rewritten {
+		return 0, nil
+	}
+	if err := moveHead(parent, oldHead); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// rawMessage returns a commit's message exactly as stored
+func rawMessage(hash string) (string, error) {
+	output, err := command("log", "-1", "--format=%B", hash).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read message of %s: %w", hash, err)
+	}
+	return strings.TrimRight(string(output), "\n") + "\n", nil
+}
+
+// recommit creates a copy of commit with a new parent and message, keeping
+// its tree and author
+func recommit(hash, parent, message string) (string, error) {
+	output, err := command("log", "-1", "--format=%an%x00%ae%x00%ad", "--date=raw", hash).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read author of %s: %w", hash, err)
+	}
+	author := strings.SplitN(strings.TrimRight(string(output), "\n"), "\x00", 3)
+	if len(author) != 3 {
+		return "", fmt.Errorf("unexpected author of %s", hash)
+	}
+
+	args := []string{"commit-tree", hash + "^{tree}", "-F", "-"}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	cmd := command(args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+author[0],
+		"GIT_AUTHOR_EMAIL="+author[1],
+		"GIT_AUTHOR_DATE="+author[2],
+	)
+	cmd.Stdin = strings.NewReader(message)
+	result, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to rewrite %s: %w", hash[:7], err)
+	}
+	return strings.TrimSpace(string(result)), nil
+}
+
+// moveHead points the current branch, or a detached HEAD, at newHead,
+// failing if HEAD moved away from oldHead in the meantime
+func moveHead(newHead, oldHead string) error {
+	ref := "HEAD"
+	args := []string{"update-ref", "--no-deref", "-m", "commet: reword"}
+	if output, err := command("symbolic-ref", "--quiet", "HEAD").Output(); err == nil {
+		ref = strings.TrimSpace(string(output))
+		args = []string{"update-ref", "-m", "commet: reword"}
+	}
+
+	args = append(args, ref, newHead, oldHead)
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// SoftReset moves the current branch to rev, keeping the index and working
+// tree as they are
+func SoftReset(rev string) error {
+	if output, err := command("reset", "--soft", rev).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset to %s: %s", rev, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// DeleteHead deletes the branch HEAD points to, leaving it unborn
+func DeleteHead() error {
+	if output, err := command("update-ref", "-d", "HEAD").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset HEAD: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bitcs/commet/internal/testutil"
+)
+
+// newHistory creates a repository with three commits by different authors
+// and returns their hashes, oldest first
+func newHistory(t *testing.T) []string {
+	t.Helper()
+	testutil.NewRepo(t)
+
+	authors := []string{"Ann <ann@example.com>", "Bob <bob@example.com>", "Cid <cid@example.com>"}
+	var hashes []string
+	for i, author := range authors {
+		testutil.WriteFile(t, "file.txt", strings.Repeat("line\n", i+1))
+		testutil.Git(t, "add", "file.txt")
+		date := fmt.Sprintf("2024-01-0%dT10:00:00+02:00", i+1)
+		testutil.Git(t, "commit", "-q", "--author", author, "--date", date, "-m", fmt.Sprintf("commit %d", i+1), "-m", "body")
+		hashes = append(hashes, testutil.Git(t, "rev-parse", "HEAD"))
+	}
+	return hashes
+}
+
+// history returns the tree, author, author date and message of every commit
+// on HEAD, oldest first
+func history(t *testing.T) []string {
+	t.Helper()
+	var entries []string
+	for _, entry := range strings.Split(testutil.Git(t, "log", "--reverse", "--format=%T %an <%ae> %ad %s|%b%x1e", "--date=raw"), "\x1e") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestRewriteMessages(t *testing.T) {
+	hashes := newHistory(t)
+	before := history(t)
+	testutil.WriteFile(t, "file.txt", "uncommitted\n")
+
+	count, err := RewriteMessages(hashes[0], map[string]string{hashes[1]: "feat: describe the change\n\nNew body\n"})
+	if err != nil {
+		t.Fatalf("RewriteMessages() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("RewriteMessages() = %d, want 1", count)
+	}
+
+	after := history(t)
+	if len(after) != 3 {
+		t.Fatalf("history has %d commits, want 3", len(after))
+	}
+	if after[0] != before[0] || after[2] != before[2] {
+		t.Errorf("untouched commits changed:\n%q\nwant\n%q", after, before)
+	}
+	tree := func(entry string) string { return strings.Fields(entry)[0] }
+	if tree(after[1]) != tree(before[1]) {
+		t.Errorf("reworded commit tree = %s, want %s", tree(after[1]), tree(before[1]))
+	}
+	if want := strings.Replace(before[1], "commit 2|body", "feat: describe the change|New body", 1); after[1] != want {
+		t.Errorf("reworded commit = %q, want %q", after[1], want)
+	}
+
+	if got := testutil.Git(t, "rev-parse", "HEAD~2"); got != hashes[0] {
+		t.Errorf("first commit = %s, want it kept as %s", got, hashes[0])
+	}
+	if got := testutil.Git(t, "rev-parse", "HEAD"); got == hashes[2] {
+		t.Error("later commit was not recreated on top of the reworded one")
+	}
+	if got := testutil.Git(t, "symbolic-ref", "HEAD"); got != "refs/heads/master" && got != "refs/heads/main" {
+		t.Errorf("HEAD = %s, want it still on the branch", got)
+	}
+	if got := testutil.Git(t, "status", "--porcelain"); got != "M file.txt" {
+		t.Errorf("status = %q, want the working tree change kept", got)
+	}
+}
+
+func TestRewriteMessagesRootCommit(t *testing.T) {
+	hashes := newHistory(t)
+	before := history(t)
+
+	count, err := RewriteMessages("", map[string]string{hashes[0]: "chore: start the file\n"})
+	if err != nil || count != 1 {
+		t.Fatalf("RewriteMessages() = %d, %v, want 1", count, err)
+	}
+
+	after := history(t)
+	if want := strings.Replace(before[0], "commit 1|body", "chore: start the file|", 1); after[0] != want {
+		t.Errorf("root commit = %q, want %q", after[0], want)
+	}
+	if got := testutil.Git(t, "rev-list", "--max-parents=0", "HEAD"); strings.Contains(got, "\n") {
+		t.Errorf("history has several roots: %s", got)
+	}
+	if after[1] != before[1] || after[2] != before[2] {
+		t.Errorf("later commits changed:\n%q\nwant\n%q", after[1:], before[1:])
+	}
+}
+
+func TestRewriteMessagesWithoutChanges(t *testing.T) {
+	hashes := newHistory(t)
+
+	count, err := RewriteMessages(hashes[0], map[string]string{})
+	if err != nil || count != 0 {
+		t.Fatalf("RewriteMessages() = %d, %v, want 0", count, err)
+	}
+	if got := testutil.Git(t, "rev-parse", "HEAD"); got != hashes[2] {
+		t.Errorf("HEAD = %s, want it unchanged at %s", got, hashes[2])
+	}
+}
+
+func TestRewriteMessagesRefusesMerges(t *testing.T) {
+	hashes := newHistory(t)
+	testutil.Git(t, "checkout", "-q", "-b", "side", hashes[1])
+	testutil.WriteFile(t, "other.txt", "other\n")
+	testutil.Git(t, "add", "other.txt")
+	testutil.Git(t, "commit", "-q", "-m", "add other")
+	testutil.Git(t, "checkout", "-q", "-")
+	testutil.Git(t, "merge", "-q", "--no-ff", "side", "-m", "merge side")
+	merge := testutil.Git(t, "rev-parse", "HEAD")
+
+	if got, err := FindMerge(hashes[0]); err != nil || got != merge {
+		t.Errorf("FindMerge() = %q, %v, want %s", got, err, merge)
+	}
+	if got, err := FindMerge(merge); err != nil || got != "" {
+		t.Errorf("FindMerge() after the merge = %q, %v, want none", got, err)
+	}
+
+	if _, err := RewriteMessages(hashes[0], map[string]string{hashes[1]: "feat: reworded\n"}); err == nil {
+		t.Fatal("RewriteMessages() over a merge succeeded, want error")
+	}
+	if got := testutil.Git(t, "rev-parse", "HEAD"); got != merge {
+		t.Errorf("HEAD = %s, want it unchanged at %s", got, merge)
+	}
+}
+
+func TestMoveHeadComparesOldHead(t *testing.T) {
+	hashes := newHistory(t)
+
+	// HEAD is at the third commit, so a caller expecting the second must fail
+	if err := moveHead(hashes[0], hashes[1]); err == nil {
+		t.Fatal("moveHead() with a stale old head succeeded, want error")
+	}
+	if got := testutil.Git(t, "rev-parse", "HEAD"); got != hashes[2] {
+		t.Errorf("HEAD = %s, want it unchanged at %s", got, hashes[2])
+	}
+
+	if err := moveHead(hashes[0], hashes[2]); err != nil {
+		t.Fatalf("moveHead() error = %v", err)
+	}
+	branch := testutil.Git(t, "symbolic-ref", "HEAD")
+	if got := testutil.Git(t, "rev-parse", branch); got != hashes[0] {
+		t.Errorf("%s = %s, want %s", branch, got, hashes[0])
+	}
+	if got := testutil.Git(t, "reflog", "-1", "--format=%gs", branch); got != "commet: reword" {
+		t.Errorf("reflog message = %q", got)
+	}
+}
+
+func TestMoveHeadDetached(t *testing.T) {
+	hashes := newHistory(t)
+	branch := testutil.Git(t, "symbolic-ref", "HEAD")
+	testutil.Git(t, "checkout", "-q", "--detach")
+
+	if err := moveHead(hashes[1], hashes[2]); err != nil {
+		t.Fatalf("moveHead() error = %v", err)
+	}
+	if got := testutil.Git(t, "rev-parse", "HEAD"); got != hashes[1] {
+		t.Errorf("HEAD = %s, want %s", got, hashes[1])
+	}
+	if got := testutil.Git(t, "rev-parse", branch); got != hashes[2] {
+		t.Errorf("%s = %s, want the branch left at %s", branch, got, hashes[2])
+	}
+}
+
+func TestRecommitKeepsTreeAndAuthor(t *testing.T) {
+	hashes := newHistory(t)
+
+	hash, err := recommit(hashes[1], "", "docs: new message\n")
+	if err != nil {
+		t.Fatalf("recommit() error = %v", err)
+	}
+	format := "--format=%T %an %ae %ad"
+	if got, want := testutil.Git(t, "log", "-1", format, "--date=raw", hash), testutil.Git(t, "log", "-1", format, "--date=raw", hashes[1]); got != want {
+		t.Errorf("recommit() = %q, want %q", got, want)
+	}
+	if got := testutil.Git(t, "log", "-1", "--format=%P|%B", hash); got != "|docs: new message" {
+		t.Errorf("parents and message = %q, want a root commit with the new message", got)
+	}
+}
+
+func TestSameTree(t *testing.T) {
+	hashes := newHistory(t)
+
+	if same, err := SameTree(hashes[0], "HEAD"); err != nil || same {
+		t.Errorf("SameTree() = %v, %v, want different trees", same, err)
+	}
+
+	// Undoing the later commits brings back the first commit's tree
+	testutil.WriteFile(t, "file.txt", "line\n")
+	testutil.Git(t, "commit", "-q", "-am", "revert to the first version")
+	if same, err := SameTree(hashes[0], "HEAD"); err != nil || !same {
+		t.Errorf("SameTree() = %v, %v, want the same tree", same, err)
+	}
+}
@@ -0,0 +1,21 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Show returns the metadata, stats and patches of a single revision, or of
+// every commit in a range such as main..feature (oldest first)
+func Show(spec string) (string, error) {
+	args := []string{"show", "--stat", "--patch", "--format=fuller", spec, "--"}
+	if strings.Contains(spec, "..") {
+		args = []string{"log", "--reverse", "--stat", "--patch", "--format=fuller", spec, "--"}
+	}
+
+	output, err := command(args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to show %s: %s", spec, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
@@ -0,0 +1,161 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// EntryKind is the kind of a git status entry
+type EntryKind int
+
+const (
+	// EntryChanged is an ordinary tracked change
+	EntryChanged EntryKind = iota
+	// EntryRenamed is a rename or copy; OrigPath holds the source
+	EntryRenamed
+	// EntryUnmerged is a path with merge conflicts
+	EntryUnmerged
+	// EntryUntracked is a path not known to git
+	EntryUntracked
+	// EntryIgnored is an ignored path
+	EntryIgnored
+)
+
+// StatusEntry is one path reported by git status --porcelain=v2
+type StatusEntry struct {
+	Kind EntryKind
+	// Path is relative to the repository root and never quoted
+	Path     string
+	OrigPath string
+	// Index and WorkTree are the X and Y status codes, '.' when unmodified
+	Index    byte
+	WorkTree byte
+	// Submodule is set when the path is a submodule
+	Submodule bool
+}
+
+// IsStaged reports whether the entry has changes in the index
+func (e StatusEntry) IsStaged() bool {
+	return (e.Kind == EntryChanged || e.Kind == EntryRenamed) && e.Index != '.'
+}
+
+// IsUnstaged reports whether the entry has tracked changes in the working tree
+func (e StatusEntry) IsUnstaged() bool {
+	return e.Kind == EntryUnmerged || ((e.Kind == EntryChanged || e.Kind == EntryRenamed) && e.WorkTree != '.')
+}
+
+// IsUntracked reports whether the entry is an untracked path
+func (e StatusEntry) IsUntracked() bool {
+	return e.Kind == EntryUntracked
+}
+
+// IsConflicted reports whether the entry has unresolved merge conflicts
+func (e StatusEntry) IsConflicted() bool {
+	return e.Kind == EntryUnmerged
+}
+
+// Code returns the two-letter status in porcelain v1 form, e.g. "M ", " M"
+// or "??"
+func (e StatusEntry) Code() string {
+	switch e.Kind {
+	case EntryUntracked:
+		return "??"
+	case EntryIgnored:
+		return "!!"
+	}
+	return strings.ReplaceAll(string([]byte{e.Index, e.WorkTree}), ".", " ")
+}
+
+// Status returns the changed, unmerged and untracked paths of the repository.
+// Untracked directories are expanded into their files.
+func Status() ([]StatusEntry, error) {
+	cmd := command("status", "--porcelain=v2", "-z", "--untracked-files=all")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git status: %w", err)
+	}
+	return ParseStatus(output)
+}
+
+// ParseStatus parses the output of git status --porcelain=v2 -z
+func ParseStatus(data []byte) ([]StatusEntry, error) {
+	// Every record ends in NUL; drop the last one so a rename missing its
+	// origin path is not paired with an empty trailing record
+	records := bytes.Split(bytes.TrimSuffix(data, []byte{0}), []byte{0})
+
+	var entries []StatusEntry
+	for i := 0; i < len(records); i++ {
+		record := string(records[i])
+		if record == "" {
+			continue
+		}
+
+		switch record[0] {
+		case '#':
+			// Header lines (--branch, --show-stash) carry no paths
+		case '1':
+			// 1 XY sub mH mI mW hH hI path
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("malformed status entry: %q", record)
+			}
+			entries = append(entries, changedEntry(EntryChanged, fields[1], fields[2], fields[8]))
+		case '2':
+			// 2 XY sub mH mI mW hH hI Xscore path, followed by the origin path
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || i+1 >= len(records) {
+				return nil, fmt.Errorf("malformed rename entry: %q", record)
+			}
+			entry := changedEntry(EntryRenamed, fields[1], fields[2], fields[9])
+			i++
+			entry.OrigPath = string(records[i])
+			entries = append(entries, entry)
+		case 'u':
+			// u XY sub m1 m2 m3 mW h1 h2 h3 path
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("malformed unmerged entry: %q", record)
+			}
+			entries = append(entries, changedEntry(EntryUnmerged, fields[1], fields[2], fields[10]))
+		case '?':
+			entries = append(entries, StatusEntry{Kind: EntryUntracked, Path: strings.TrimPrefix(record, "? "), Index: '?', WorkTree: '?'})
+		case '!':
+			entries = append(entries, StatusEntry{Kind: EntryIgnored, Path: strings.TrimPrefix(record, "! "), Index: '!', WorkTree: '!'})
+		default:
+			return nil, fmt.Errorf("unknown status entry: %q", record)
+		}
+	}
+	return entries, nil
+}
+
+func changedEntry(kind EntryKind, xy, sub, path string) StatusEntry {
+	entry := StatusEntry{
+		Kind:      kind,
+		Path:      path,
+		Index:     '.',
+		WorkTree:  '.',
+		Submodule: strings.HasPrefix(sub, "S"),
+	}
+	if len(xy) == 2 {
+		entry.Index = xy[0]
+		entry.WorkTree = xy[1]
+	}
+	return entry
+}
+
+// statusPaths returns the paths of the entries accepted by keep
+func statusPaths(keep func(StatusEntry) bool) ([]string, error) {
+	entries, err := Status()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, e := range entries {
+		if keep(e) {
+			files = append(files, e.Path)
+		}
+	}
+	return files, nil
+}
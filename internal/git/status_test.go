@@ -0,0 +1,135 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	h1 = "1111111111111111111111111111111111111111"
+	h2 = "2222222222222222222222222222222222222222"
+	h3 = "3333333333333333333333333333333333333333"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []string
+		want    []StatusEntry
+	}{
+		{
+			name:    "modified in worktree",
+			records: []string{"1 .M N... 100644 100644 100644 " + h1 + " " + h1 + " main.go"},
+			want:    []StatusEntry{{Kind: EntryChanged, Path: "main.go", Index: '.', WorkTree: 'M'}},
+		},
+		{
+			name:    "added with a space in the path",
+			records: []string{"1 A. N... 000000 100644 100644 " + h1 + " " + h2 + " docs/read me.md"},
+			want:    []StatusEntry{{Kind: EntryChanged, Path: "docs/read me.md", Index: 'A', WorkTree: '.'}},
+		},
+		{
+			name:    "submodule",
+			records: []string{"1 .M SC.. 160000 160000 160000 " + h1 + " " + h1 + " vendor/lib"},
+			want:    []StatusEntry{{Kind: EntryChanged, Path: "vendor/lib", Index: '.', WorkTree: 'M', Submodule: true}},
+		},
+		{
+			name:    "rename",
+			records: []string{"2 R. N... 100644 100644 100644 " + h1 + " " + h1 + " R100 new.go", "old.go"},
+			want:    []StatusEntry{{Kind: EntryRenamed, Path: "new.go", OrigPath: "old.go", Index: 'R', WorkTree: '.'}},
+		},
+		{
+			name:    "copy with worktree changes",
+			records: []string{"2 CM N... 100644 100644 100644 " + h1 + " " + h2 + " C75 copy.go", "src.go"},
+			want:    []StatusEntry{{Kind: EntryRenamed, Path: "copy.go", OrigPath: "src.go", Index: 'C', WorkTree: 'M'}},
+		},
+		{
+			name:    "unmerged",
+			records: []string{"u UU N... 100644 100644 100644 100644 " + h1 + " " + h2 + " " + h3 + " conflict.go"},
+			want:    []StatusEntry{{Kind: EntryUnmerged, Path: "conflict.go", Index: 'U', WorkTree: 'U'}},
+		},
+		{
+			name:    "untracked and ignored",
+			records: []string{"? new file.txt", "! build/out"},
+			want: []StatusEntry{
+				{Kind: EntryUntracked, Path: "new file.txt", Index: '?', WorkTree: '?'},
+				{Kind: EntryIgnored, Path: "build/out", Index: '!', WorkTree: '!'},
+			},
+		},
+		{
+			name: "headers are skipped",
+			records: []string{
+				"# branch.oid " + h1,
+				"# branch.head main",
+				"2 R. N... 100644 100644 100644 " + h1 + " " + h1 + " R100 b.go", "a.go",
+				"? c.go",
+			},
+			want: []StatusEntry{
+				{Kind: EntryRenamed, Path: "b.go", OrigPath: "a.go", Index: 'R', WorkTree: '.'},
+				{Kind: EntryUntracked, Path: "c.go", Index: '?', WorkTree: '?'},
+			},
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(strings.Join(tt.records, "\x00"))
+			if len(tt.records) > 0 {
+				data = append(data, 0)
+			}
+
+			got, err := ParseStatus(data)
+			if err != nil {
+				t.Fatalf("ParseStatus() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusMalformed(t *testing.T) {
+	tests := map[string]string{
+		"short ordinary":     "1 .M N... main.go\x00",
+		"rename without src": "2 R. N... 100644 100644 100644 " + h1 + " " + h1 + " R100 new.go\x00",
+		"short unmerged":     "u UU N... conflict.go\x00",
+		"unknown record":     "x what\x00",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseStatus([]byte(data)); err == nil {
+				t.Errorf("ParseStatus(%q) succeeded, want error", data)
+			}
+		})
+	}
+}
+
+func TestStatusEntryPredicates(t *testing.T) {
+	tests := []struct {
+		entry                       StatusEntry
+		staged, unstaged, untracked bool
+		code                        string
+	}{
+		{StatusEntry{Kind: EntryChanged, Index: 'M', WorkTree: '.'}, true, false, false, "M "},
+		{StatusEntry{Kind: EntryChanged, Index: '.', WorkTree: 'D'}, false, true, false, " D"},
+		{StatusEntry{Kind: EntryRenamed, Index: 'R', WorkTree: 'M'}, true, true, false, "RM"},
+		{StatusEntry{Kind: EntryUnmerged, Index: 'U', WorkTree: 'U'}, false, true, false, "UU"},
+		{StatusEntry{Kind: EntryUntracked, Index: '?', WorkTree: '?'}, false, false, true, "??"},
+	}
+
+	for _, tt := range tests {
+		e := tt.entry
+		if e.IsStaged() != tt.staged || e.IsUnstaged() != tt.unstaged || e.IsUntracked() != tt.untracked {
+			t.Errorf("%s: staged=%v unstaged=%v untracked=%v, want %v %v %v",
+				e.Code(), e.IsStaged(), e.IsUnstaged(), e.IsUntracked(), tt.staged, tt.unstaged, tt.untracked)
+		}
+		if e.Code() != tt.code {
+			t.Errorf("Code() = %q, want %q", e.Code(), tt.code)
+		}
+	}
+}
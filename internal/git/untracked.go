@@ -1,26 +1,12 @@
 package git
 
-import (
-	"fmt"
-	"os/exec"
-	"strings"
-)
+import "fmt"
 
 // GetUntrackedFiles returns list of untracked files
 func GetUntrackedFiles() ([]string, error) {
-	cmd := exec.Command("git", "ls-files", "--others", "--exclude-standard")
-	output, err := cmd.Output()
+	files, err := statusPaths(StatusEntry.IsUntracked)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get untracked files: %w", err)
 	}
-
-	files := []string{}
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line != "" {
-			files = append(files, line)
-		}
-	}
-
 	return files, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcs/commet/internal/prompts"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// CommitClass is the model's changelog classification of a commit
+type CommitClass struct {
+	// Section is a Keep a Changelog category or "Skip"
+	Section  string
+	Entry    string
+	Breaking bool
+}
+
+// ClassifyCommits sorts commit messages into changelog sections. The result
+// has one element per message; messages the model left out have an empty
+// section.
+func (s *Service) ClassifyCommits(ctx context.Context, messages []string) ([]CommitClass, error) {
+	prompt := prompts.ClassifyCommitsPrompt(messages)
+	opts := append(s.callOptions(), llms.WithJSONMode())
+
+	response, err := s.generate(ctx, s.messages(prompt), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to classify commits: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from LLM")
+	}
+
+	var result struct {
+		Commits []struct {
+			ID       int    `json:"id"`
+			Section  string `json:"section"`
+			Entry    string `json:"entry"`
+			Breaking bool   `json:"breaking"`
+		} `json:"commits"`
+	}
+	if err := decodeObject(response.Choices[0].Content, "classification", &result); err != nil {
+		return nil, err
+	}
+
+	classes := make([]CommitClass, len(messages))
+	for _, c := range result.Commits {
+		if c.ID < 1 || c.ID > len(messages) {
+			continue
+		}
+		classes[c.ID-1] = CommitClass{
+			Section:  c.Section,
+			Entry:    cleanSubject(c.Entry),
+			Breaking: c.Breaking,
+		}
+	}
+	return classes, nil
+}
@@ -0,0 +1,33 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/prompts"
+)
+
+// ExplainChanges explains the git show or log output of a commit or range in
+// plain language, with the amount of detail set by depth
+func (s *Service) ExplainChanges(ctx context.Context, spec, history string, depth prompts.Depth) (string, error) {
+	history, _ = truncate(history, maxPromptInput, "history")
+	prompt := prompts.ExplainPrompt(spec, history, depth)
+
+	response, err := s.generate(ctx, s.messages(prompt), s.callOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to explain changes: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+
+	explanation := strings.TrimSpace(response.Choices[0].Content)
+	if m := fencePattern.FindStringSubmatch(explanation); m != nil && strings.HasPrefix(explanation, "```") {
+		explanation = strings.TrimSpace(m[1])
+	}
+	if explanation == "" {
+		return "", fmt.Errorf("LLM returned an empty explanation")
+	}
+	return explanation, nil
+}
@@ -0,0 +1,207 @@
+// Package fake provides an llms.Model that answers with canned responses.
+// It backs the "fake" provider so the commit flow can run in CI and demos
+// without network access or API keys.
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// FixturesEnv names the environment variable holding the path of a fixtures
+// file used by the fake provider
+const FixturesEnv = "COMMET_FAKE_FIXTURES"
+
+// DefaultResponse is returned when no fixture matches a prompt. It is a valid
+// structured commit message so the commit flow completes unchanged.
+const DefaultResponse = `{"type": "chore", "scope": "", "subject": "update {{.Files}} file(s)", "body": "", "breaking_change": "", "footers": []}`
+
+// Fixture maps prompts matching a pattern to a response. Response is a
+// text/template executed with Data; when Error is set the call fails instead.
+type Fixture struct {
+	Match    string `json:"match"`
+	Response string `json:"response"`
+	Error    string `json:"error"`
+
+	pattern  *regexp.Regexp
+	template *template.Template
+}
+
+// Data is passed to response templates
+type Data struct {
+	// Prompt is the text of all messages in the request
+	Prompt string
+	// Call is the 1-based number of the request made to the model
+	Call int
+	// Files is the number of files in a diff contained in the prompt
+	Files int
+}
+
+// Call records a request made to the model
+type Call struct {
+	Messages []llms.MessageContent
+	Options  llms.CallOptions
+	Prompt   string
+}
+
+// Model is an llms.Model returning responses from fixtures
+type Model struct {
+	mu       sync.Mutex
+	fixtures []*Fixture
+	fallback *Fixture
+	calls    []Call
+}
+
+var _ llms.Model = (*Model)(nil)
+
+// New returns a model answering every prompt with DefaultResponse
+func New() *Model {
+	m, err := WithFixtures(nil)
+	if err != nil {
+		// DefaultResponse is a constant template
+		panic(err)
+	}
+	return m
+}
+
+// WithFixtures returns a model answering with the first fixture whose Match
+// pattern matches the prompt. A fixture with an empty pattern matches any
+// prompt; DefaultResponse is used when nothing matches.
+func WithFixtures(fixtures []Fixture) (*Model, error) {
+	m := &Model{}
+	for i := range fixtures {
+		f := fixtures[i]
+		if err := f.compile(); err != nil {
+			return nil, fmt.Errorf("fixture %d: %w", i+1, err)
+		}
+		m.fixtures = append(m.fixtures, &f)
+	}
+
+	m.fallback = &Fixture{Response: DefaultResponse}
+	if err := m.fallback.compile(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Load reads fixtures from a JSON file containing an array of fixtures
+func Load(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read fixtures: %w", err)
+	}
+
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("invalid fixtures file %s: %w", path, err)
+	}
+	return WithFixtures(fixtures)
+}
+
+// FromEnv loads the fixtures named by FixturesEnv, or returns New() when unset
+func FromEnv() (*Model, error) {
+	if path := os.Getenv(FixturesEnv); path != "" {
+		return Load(path)
+	}
+	return New(), nil
+}
+
+func (f *Fixture) compile() error {
+	if f.Match != "" {
+		pattern, err := regexp.Compile(f.Match)
+		if err != nil {
+			return fmt.Errorf("invalid match pattern: %w", err)
+		}
+		f.pattern = pattern
+	}
+
+	tmpl, err := template.New("response").Parse(f.Response)
+	if err != nil {
+		return fmt.Errorf("invalid response template: %w", err)
+	}
+	f.template = tmpl
+	return nil
+}
+
+// Calls returns the requests made to the model so far
+func (m *Model) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Call implements llms.Model
+func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// GenerateContent implements llms.Model
+func (m *Model) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	prompt := promptText(messages)
+
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Messages: messages, Options: opts, Prompt: prompt})
+	data := Data{Prompt: prompt, Call: len(m.calls), Files: strings.Count(prompt, "diff --git ")}
+	fixture := m.match(prompt)
+	m.mu.Unlock()
+
+	if fixture.Error != "" {
+		return nil, errors.New(fixture.Error)
+	}
+
+	var out bytes.Buffer
+	if err := fixture.template.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("fake response template: %w", err)
+	}
+
+	// Rough token counts let usage reporting work against the fake provider
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:    out.String(),
+			StopReason: "stop",
+			GenerationInfo: map[string]any{
+				"PromptTokens":     len(prompt) / 4,
+				"CompletionTokens": out.Len() / 4,
+			},
+		}},
+	}, nil
+}
+
+func (m *Model) match(prompt string) *Fixture {
+	for _, f := range m.fixtures {
+		if f.pattern == nil || f.pattern.MatchString(prompt) {
+			return f
+		}
+	}
+	return m.fallback
+}
+
+func promptText(messages []llms.MessageContent) string {
+	var parts []string
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				parts = append(parts, text.Text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,73 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+func generate(t *testing.T, m *Model, prompt string) (string, error) {
+	t.Helper()
+	resp, err := m.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Choices[0].Content, nil
+}
+
+func TestFixtureTemplatesAndOrder(t *testing.T) {
+	m, err := WithFixtures([]Fixture{
+		{Match: "^explain", Response: "call {{.Call}}"},
+		{Match: "diff --git", Response: "{{.Files}} files"},
+		{Response: "catch-all"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{"explain diff --git a/x b/x", "call 1"},
+		{"diff --git a/x b/x\ndiff --git a/y b/y", "2 files"},
+		{"anything else", "catch-all"},
+	}
+	for _, tt := range tests {
+		got, err := generate(t, m, tt.prompt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("response to %q = %q, want %q", tt.prompt, got, tt.want)
+		}
+	}
+	if n := len(m.Calls()); n != len(tests) {
+		t.Errorf("recorded %d calls, want %d", n, len(tests))
+	}
+}
+
+func TestFixtureError(t *testing.T) {
+	m, err := WithFixtures([]Fixture{{Match: "boom", Error: "exploded"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generate(t, m, "boom"); err == nil || err.Error() != "exploded" {
+		t.Errorf("error = %v, want exploded", err)
+	}
+	if got, err := generate(t, m, "fine"); err != nil || got == "" {
+		t.Errorf("default response = %q, %v", got, err)
+	}
+}
+
+func TestInvalidFixtures(t *testing.T) {
+	if _, err := WithFixtures([]Fixture{{Match: "("}}); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+	if _, err := WithFixtures([]Fixture{{Response: "{{.Nope"}}); err == nil {
+		t.Error("invalid template accepted")
+	}
+}
@@ -0,0 +1,28 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/bitcs/commet/internal/prompts"
+	"github.com/bitcs/commet/internal/review"
+	"github.com/bitcs/commet/internal/usage"
+)
+
+// Generator produces commit messages from a diff. Service implements it;
+// commands depend on the interface so they can run against a stand-in.
+type Generator interface {
+	GenerateCommitMessage(ctx context.Context, gitDiff string) (string, error)
+	GenerateSplitPlan(ctx context.Context, changes string) ([]PlannedCommit, error)
+	GeneratePullRequest(ctx context.Context, commits, gitDiff, template string) (*PullRequest, error)
+	ClassifyCommits(ctx context.Context, messages []string) ([]CommitClass, error)
+	GenerateReleaseNotes(ctx context.Context, version, changes, commits string) (string, error)
+	ReviewDiff(ctx context.Context, gitDiff string) ([]review.Finding, error)
+	ExplainChanges(ctx context.Context, spec, history string, depth prompts.Depth) (string, error)
+	GenerateSquashMessage(ctx context.Context, messages, gitDiff string) (string, error)
+	// FromCache reports whether the last message came from the cache
+	FromCache() bool
+	// Usage returns the tokens and cost spent so far
+	Usage() usage.Usage
+}
+
+var _ Generator = (*Service)(nil)
@@ -0,0 +1,190 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// modelCacheTTL controls how long a fetched model list is reused
+const modelCacheTTL = 24 * time.Hour
+
+// ModelList is the result of a model lookup for one provider
+type ModelList struct {
+	Provider  config.Provider `json:"provider"`
+	Models    []string        `json:"models"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	// Static is set when the built-in list was used instead of the API
+	Static bool `json:"-"`
+}
+
+// ListModels returns the models offered by provider. Results are cached for
+// modelCacheTTL unless refresh is set; when the provider cannot be reached the
+// built-in list is returned together with the error.
+func ListModels(ctx context.Context, cfg *config.Config, provider config.Provider, refresh bool) (ModelList, error) {
+	cache := loadModelCache()
+	if cached, ok := cache[provider.String()]; ok && !refresh && time.Since(cached.FetchedAt) < modelCacheTTL {
+		return cached, nil
+	}
+
+	models, err := fetchModels(ctx, provider, cfg.AI.APIKeys[provider.String()])
+	if err != nil {
+		return ModelList{
+			Provider: provider,
+			Models:   config.GetAvailableModels(provider),
+			Static:   true,
+		}, err
+	}
+
+	list := ModelList{Provider: provider, Models: models, FetchedAt: time.Now()}
+	cache[provider.String()] = list
+	saveModelCache(cache)
+	return list, nil
+}
+
+func fetchModels(ctx context.Context, provider config.Provider, apiKey string) ([]string, error) {
+	if !provider.RequiresAPIKey() {
+		return config.GetAvailableModels(provider), nil
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key configured for %s", provider)
+	}
+
+	var (
+		url     string
+		headers = map[string]string{}
+	)
+
+	switch provider {
+	case config.ProviderOpenAI:
+		url = "https://api.openai.com/v1/models"
+		headers["Authorization"] = "Bearer " + apiKey
+	case config.ProviderGroq:
+		url = "https://api.groq.com/openai/v1/models"
+		headers["Authorization"] = "Bearer " + apiKey
+	case config.ProviderClaude:
+		url = "https://api.anthropic.com/v1/models?limit=100"
+		headers["x-api-key"] = apiKey
+		headers["anthropic-version"] = "2023-06-01"
+	case config.ProviderGoogle:
+		url = "https://generativelanguage.googleapis.com/v1beta/models?pageSize=1000"
+		headers["x-goog-api-key"] = apiKey
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", provider)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build models request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list models: %s returned %s", provider, resp.Status)
+	}
+
+	var body struct {
+		// OpenAI, Groq and Anthropic
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+		// Google
+		Models []struct {
+			Name                       string   `json:"name"`
+			SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode models response: %w", err)
+	}
+
+	models := []string{}
+	for _, m := range body.Data {
+		if isChatModel(provider, m.ID) {
+			models = append(models, m.ID)
+		}
+	}
+	for _, m := range body.Models {
+		for _, method := range m.SupportedGenerationMethods {
+			if method == "generateContent" {
+				models = append(models, strings.TrimPrefix(m.Name, "models/"))
+				break
+			}
+		}
+	}
+
+	if len(models) == 0 {
+		return nil, fmt.Errorf("%s returned no usable models", provider)
+	}
+
+	sort.Strings(models)
+	return models, nil
+}
+
+// isChatModel filters out embedding, audio and image models that cannot
+// write commit messages
+func isChatModel(provider config.Provider, id string) bool {
+	if provider != config.ProviderOpenAI && provider != config.ProviderGroq {
+		return true
+	}
+	for _, skip := range []string{"embedding", "whisper", "tts", "dall-e", "moderation", "audio", "realtime", "transcribe", "image", "guard"} {
+		if strings.Contains(id, skip) {
+			return false
+		}
+	}
+	return true
+}
+
+func modelCachePath() (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "models.json"), nil
+}
+
+func loadModelCache() map[string]ModelList {
+	cache := map[string]ModelList{}
+	path, err := modelCachePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	// A corrupt cache is simply ignored and rewritten on the next fetch
+	_ = json.Unmarshal(data, &cache)
+	return cache
+}
+
+func saveModelCache(cache map[string]ModelList) {
+	path, err := modelCachePath()
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0600)
+}
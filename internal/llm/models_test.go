@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+func TestListModelsUsesCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := &config.Config{}
+	provider := config.ProviderOpenAI
+
+	// Without an API key the provider cannot be asked, so the built-in list
+	// is returned along with the error
+	list, err := ListModels(context.Background(), cfg, provider, false)
+	if err == nil || !list.Static || !reflect.DeepEqual(list.Models, config.GetAvailableModels(provider)) {
+		t.Errorf("ListModels() = %+v, %v, want the static list and an error", list, err)
+	}
+
+	cached := ModelList{Provider: provider, Models: []string{"cached-model"}, FetchedAt: time.Now()}
+	saveModelCache(map[string]ModelList{provider.String(): cached})
+	if list, err := ListModels(context.Background(), cfg, provider, false); err != nil || !reflect.DeepEqual(list.Models, cached.Models) {
+		t.Errorf("ListModels() = %+v, %v, want the cached list", list, err)
+	}
+
+	if list, _ := ListModels(context.Background(), cfg, provider, true); !list.Static {
+		t.Errorf("ListModels() with refresh = %+v, want a new lookup", list)
+	}
+
+	cached.FetchedAt = time.Now().Add(-2 * modelCacheTTL)
+	saveModelCache(map[string]ModelList{provider.String(): cached})
+	if list, _ := ListModels(context.Background(), cfg, provider, false); !list.Static {
+		t.Errorf("ListModels() with an expired cache = %+v, want a new lookup", list)
+	}
+}
+
+func TestIsChatModel(t *testing.T) {
+	tests := []struct {
+		provider config.Provider
+		id       string
+		want     bool
+	}{
+		{config.ProviderOpenAI, "gpt-4o", true},
+		{config.ProviderOpenAI, "text-embedding-3-small", false},
+		{config.ProviderOpenAI, "whisper-1", false},
+		{config.ProviderGroq, "llama-guard-3-8b", false},
+		{config.ProviderClaude, "claude-sonnet-4-20250514", true},
+	}
+	for _, tt := range tests {
+		if got := isChatModel(tt.provider, tt.id); got != tt.want {
+			t.Errorf("isChatModel(%s, %q) = %v, want %v", tt.provider, tt.id, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package llm
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// bodyWidth is the conventional column limit for commit message bodies
+const bodyWidth = 72
+
+var (
+	fencePattern    = regexp.MustCompile("(?s)```[a-zA-Z]*\\n?(.*?)```")
+	preamblePattern = regexp.MustCompile(`(?i)^(here('s| is| are)|sure|certainly|okay|ok,|below is|the following)\b.*:\s*$`)
+	labelPattern    = regexp.MustCompile(`(?i)^\**(suggested )?commit message\**:\s*`)
+	trailerPattern  = regexp.MustCompile(`^(BREAKING CHANGE|[A-Z][a-z]+(-[A-Za-z]+)+|Refs|Closes|Fixes|Resolves): `)
+	bulletPattern   = regexp.MustCompile(`^(\s*)([-*]|\d+\.) `)
+	headingPattern  = regexp.MustCompile(`^#{1,6}\s+`)
+)
+
+// NormalizeCommitMessage cleans up a model response into a commit message:
+// code fences, chatty preambles and quoting are removed, the subject is
+// separated from the body by a blank line and the body is wrapped at 72
+// columns. Subject length is left to the caller.
+func NormalizeCommitMessage(raw string) string {
+	text := strings.ReplaceAll(strings.TrimSpace(raw), "\r\n", "\n")
+
+	if m := fencePattern.FindStringSubmatch(text); m != nil {
+		text = strings.TrimSpace(m[1])
+	}
+	text = strings.Trim(text, "`")
+
+	lines := strings.Split(text, "\n")
+	for len(lines) > 0 {
+		first := strings.TrimSpace(lines[0])
+		if first == "" || preamblePattern.MatchString(first) {
+			lines = lines[1:]
+			continue
+		}
+		break
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	subject := cleanSubject(lines[0])
+	body := strings.TrimSpace(strings.Join(lines[1:], "\n"))
+	if body == "" {
+		return subject
+	}
+	return subject + "\n\n" + wrapBody(body, bodyWidth)
+}
+
+// SplitSubject returns the first line of a message and the remaining body
+func SplitSubject(message string) (string, string) {
+	subject, body, _ := strings.Cut(message, "\n")
+	return subject, strings.TrimSpace(body)
+}
+
+// TruncateSubject shortens a subject to at most limit characters, cutting at
+// a word boundary and dropping dangling connectives and punctuation
+func TruncateSubject(subject string, limit int) string {
+	if limit <= 0 || len([]rune(subject)) <= limit {
+		return subject
+	}
+
+	runes := []rune(subject)
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, " "); i >= 0 && utf8.RuneCountInString(cut[:i]) > limit/2 {
+		cut = cut[:i]
+	}
+
+	words := strings.Fields(cut)
+	for len(words) > 1 {
+		last := strings.ToLower(strings.TrimRight(words[len(words)-1], ",;:-"))
+		switch last {
+		case "and", "or", "the", "a", "an", "to", "for", "of", "in", "with", "on", "":
+			words = words[:len(words)-1]
+			continue
+		}
+		break
+	}
+	return strings.TrimRight(strings.Join(words, " "), ",;:- ")
+}
+
+func cleanSubject(line string) string {
+	subject := strings.TrimSpace(line)
+	subject = labelPattern.ReplaceAllString(subject, "")
+	// Only a Markdown heading marker; "#123 ..." starts with an issue reference
+	subject = headingPattern.ReplaceAllString(subject, "")
+	subject = strings.Trim(subject, "*")
+	for _, q := range []string{`"`, "'", "`"} {
+		if len(subject) > 1 && strings.HasPrefix(subject, q) && strings.HasSuffix(subject, q) {
+			subject = subject[1 : len(subject)-1]
+		}
+	}
+	subject = strings.TrimSpace(subject)
+	if strings.HasSuffix(subject, ".") && !strings.HasSuffix(subject, "..") {
+		subject = strings.TrimSuffix(subject, ".")
+	}
+	return subject
+}
+
+// wrapBody re-flows paragraphs and list items to width, leaving trailers and
+// lines without spaces (URLs, paths) untouched
+func wrapBody(body string, width int) string {
+	var out []string
+	var paragraph []string
+	prefix, indent := "", ""
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		out = append(out, wrapText(strings.Join(paragraph, " "), width, prefix, indent)...)
+		paragraph = nil
+		prefix, indent = "", ""
+	}
+
+	blank := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		case trailerPattern.MatchString(trimmed) || !strings.Contains(trimmed, " "):
+			flush()
+			out = append(out, trimmed)
+		case bulletPattern.MatchString(line):
+			flush()
+			m := bulletPattern.FindStringSubmatch(line)
+			prefix = m[0]
+			indent = strings.Repeat(" ", len(m[0]))
+			paragraph = append(paragraph, strings.TrimSpace(line[len(m[0]):]))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+		blank = false
+	}
+	flush()
+
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+func wrapText(text string, width int, prefix, indent string) []string {
+	var lines []string
+	current := prefix
+	for _, word := range strings.Fields(text) {
+		switch {
+		case current == prefix || current == indent:
+			current += word
+		case len(current)+1+len(word) > width:
+			lines = append(lines, current)
+			current = indent + word
+		default:
+			current += " " + word
+		}
+	}
+	return append(lines, current)
+}
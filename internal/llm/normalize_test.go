@@ -0,0 +1,51 @@
+package llm
+
+import "testing"
+
+func TestTruncateSubject(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		limit   int
+		want    string
+	}{
+		{"fits", "add login page", 50, "add login page"},
+		{"no limit", "add login page", 0, "add login page"},
+		{"word boundary", "add support for exporting reports as CSV files", 30, "add support for exporting"},
+		{"dangling connective", "fix crash and leak in the parser", 14, "fix crash"},
+		{"trailing punctuation", "refactor config, cache: and retry", 17, "refactor config"},
+		{"single long word", "supercalifragilisticexpialidocious", 10, "supercalif"},
+		{"non-ASCII", "überarbeite Größenberechnung für Bilder", 30, "überarbeite Größenberechnung"},
+		{"non-ASCII short first word", "äääää bcdefghij", 10, "äääää bcde"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateSubject(tt.subject, tt.limit); got != tt.want {
+				t.Errorf("TruncateSubject(%q, %d) = %q, want %q", tt.subject, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanSubject(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"add login page", "add login page"},
+		{"  add login page.  ", "add login page"},
+		{"## add login page", "add login page"},
+		{"# add login page", "add login page"},
+		{"#123 fix crash on empty input", "#123 fix crash on empty input"},
+		{"Commit message: add login page", "add login page"},
+		{"**add login page**", "add login page"},
+		{`"add login page"`, "add login page"},
+		{"`add login page`", "add login page"},
+		{"wait for it...", "wait for it..."},
+	}
+	for _, tt := range tests {
+		if got := cleanSubject(tt.line); got != tt.want {
+			t.Errorf("cleanSubject(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
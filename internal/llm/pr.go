@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/prompts"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// PullRequest is a generated pull request title and Markdown body
+type PullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// GeneratePullRequest writes a pull request title and description from the
+// branch's commit messages, its diff and an optional template
+func (s *Service) GeneratePullRequest(ctx context.Context, commits, gitDiff, template string) (*PullRequest, error) {
+	gitDiff, _ = truncate(gitDiff, maxPromptInput, "diff")
+	prompt := prompts.PullRequestPrompt(commits, gitDiff, template)
+	opts := append(s.callOptions(), llms.WithJSONMode())
+
+	response, err := s.generate(ctx, s.messages(prompt), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pull request: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from LLM")
+	}
+
+	var pr PullRequest
+	if err := decodeObject(response.Choices[0].Content, "pull request", &pr); err != nil {
+		return nil, err
+	}
+	pr.Title = cleanSubject(pr.Title)
+	pr.Body = strings.TrimSpace(pr.Body)
+	if pr.Title == "" {
+		return nil, fmt.Errorf("LLM returned an empty pull request title")
+	}
+	return &pr, nil
+}
+
+// String renders the pull request as a title line followed by the body
+func (pr PullRequest) String() string {
+	if pr.Body == "" {
+		return pr.Title
+	}
+	return pr.Title + "\n\n" + pr.Body
+}
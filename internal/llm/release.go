@@ -0,0 +1,34 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/prompts"
+)
+
+// GenerateReleaseNotes writes Markdown release notes for version from the
+// categorized changes and the commit messages of the release
+func (s *Service) GenerateReleaseNotes(ctx context.Context, version, changes, commits string) (string, error) {
+	commits, _ = truncate(commits, maxPromptInput, "commits")
+	prompt := prompts.ReleaseNotesPrompt(version, changes, commits)
+
+	response, err := s.generate(ctx, s.messages(prompt), s.callOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate release notes: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+
+	notes := strings.TrimSpace(response.Choices[0].Content)
+	// Models like to wrap Markdown answers in a fence
+	if m := fencePattern.FindStringSubmatch(notes); m != nil && strings.HasPrefix(notes, "```") {
+		notes = strings.TrimSpace(m[1])
+	}
+	if notes == "" {
+		return "", fmt.Errorf("LLM returned empty release notes")
+	}
+	return notes, nil
+}
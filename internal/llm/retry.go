@@ -0,0 +1,141 @@
+package llm
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryTransport retries requests that fail with a rate limit or server
+// error, honoring the Retry-After header when the provider sends one
+type retryTransport struct {
+	base        http.RoundTripper
+	maxAttempts int
+}
+
+func newRetryClient(maxAttempts int) *http.Client {
+	return &http.Client{Transport: &retryTransport{base: http.DefaultTransport, maxAttempts: maxAttempts}}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		canReplay := req.Body == nil || req.GetBody != nil
+		if err != nil || !retryableStatus(resp.StatusCode) || attempt >= t.maxAttempts || !canReplay {
+			return resp, err
+		}
+
+		delay := backoff(attempt, retryAfter(resp.Header.Get("Retry-After")))
+		if !fitsDeadline(req.Context(), delay) {
+			// Waiting would only end in a timeout; let the caller fall back
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryableStatus reports whether an HTTP status is worth retrying. 529 is
+// Anthropic's "overloaded" status.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout, 529:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryableError reports whether an error from a gRPC based provider is
+// transient
+func retryableError(err error) bool {
+	switch status.Code(err) {
+	case codes.ResourceExhausted, codes.Unavailable, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
+
+// withRetry calls fn until it succeeds, returns a non-retryable error or
+// maxAttempts is reached
+func withRetry(ctx context.Context, maxAttempts int, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !retryableError(err) || attempt >= maxAttempts {
+			return err
+		}
+		delay := backoff(attempt, 0)
+		if !fitsDeadline(ctx, delay) {
+			return err
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt: the server supplied
+// Retry-After when present, otherwise exponential backoff with full jitter
+func backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, retryMaxDelay)
+	}
+	ceiling := min(retryBaseDelay<<(attempt-1), retryMaxDelay)
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// fitsDeadline reports whether waiting for delay leaves time for another
+// attempt before the context's deadline
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > delay
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":     0,
+		"0":    0,
+		"3":    3 * time.Second,
+		"soon": 0,
+	}
+	for header, want := range tests {
+		if got := retryAfter(header); got != want {
+			t.Errorf("retryAfter(%q) = %s, want %s", header, got, want)
+		}
+	}
+
+	future := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if got := retryAfter(future); got < 8*time.Second || got > 10*time.Second {
+		t.Errorf("retryAfter(%q) = %s, want about 10s", future, got)
+	}
+	// A date in the past leaves nothing to wait for
+	if got := retryAfter("Wed, 21 Oct 2015 07:28:00 GMT"); got > 0 {
+		t.Errorf("retryAfter() of a past date = %s, want no wait", got)
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	if got := backoff(1, 2*time.Second); got != 2*time.Second {
+		t.Errorf("backoff() with Retry-After = %s, want 2s", got)
+	}
+	if got := backoff(1, time.Hour); got != retryMaxDelay {
+		t.Errorf("backoff() with a long Retry-After = %s, want the %s cap", got, retryMaxDelay)
+	}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		ceiling := min(retryBaseDelay<<(attempt-1), retryMaxDelay)
+		for i := 0; i < 50; i++ {
+			if got := backoff(attempt, 0); got <= 0 || got > ceiling {
+				t.Fatalf("backoff(%d) = %s, want within (0, %s]", attempt, got, ceiling)
+			}
+		}
+	}
+}
+
+// scriptedTransport answers with the given statuses in turn
+type scriptedTransport struct {
+	statuses []int
+	headers  http.Header
+	calls    int
+	bodies   []string
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		s.bodies = append(s.bodies, string(body))
+	}
+	code := s.statuses[min(s.calls, len(s.statuses)-1)]
+	s.calls++
+	return &http.Response{StatusCode: code, Header: s.headers, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		maxAttempts int
+		wantStatus  int
+		wantCalls   int
+	}{
+		{"success", []int{200}, 3, 200, 1},
+		{"retried until success", []int{429, 503, 200}, 3, 200, 3},
+		{"not retryable", []int{400, 200}, 3, 400, 1},
+		{"attempts exhausted", []int{529}, 2, 529, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &scriptedTransport{statuses: tt.statuses, headers: http.Header{"Retry-After": {"0"}}}
+			client := &http.Client{Transport: &retryTransport{base: base, maxAttempts: tt.maxAttempts}}
+
+			resp, err := client.Post("http://example.invalid", "application/json", strings.NewReader(`{"a":1}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus || base.calls != tt.wantCalls {
+				t.Errorf("status %d after %d calls, want %d after %d", resp.StatusCode, base.calls, tt.wantStatus, tt.wantCalls)
+			}
+			for _, body := range base.bodies {
+				if body != `{"a":1}` {
+					t.Errorf("retried request body = %q, want it replayed", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportRespectsDeadline(t *testing.T) {
+	base := &scriptedTransport{statuses: []int{429, 200}, headers: http.Header{"Retry-After": {"20"}}}
+	client := &http.Client{Transport: &retryTransport{base: base, maxAttempts: 3}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.invalid", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 429 || base.calls != 1 {
+		t.Errorf("status %d after %d calls, want the 429 returned without waiting", resp.StatusCode, base.calls)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	calls := 0
+	err := withRetry(context.Background(), 3, func() error {
+		calls++
+		if calls < 2 {
+			return status.Error(codes.Unavailable, "busy")
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("withRetry() = %v after %d calls, want success after 2", err, calls)
+	}
+
+	calls = 0
+	permanent := errors.New("bad request")
+	if err := withRetry(context.Background(), 3, func() error { calls++; return permanent }); err != permanent || calls != 1 {
+		t.Errorf("withRetry() = %v after %d calls, want the permanent error at once", err, calls)
+	}
+}
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/prompts"
+	"github.com/bitcs/commet/internal/review"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ReviewDiff asks the model to review a diff and returns its findings sorted
+// by descending severity. A diff too large to send whole is reviewed in part,
+// and a high severity review.CategoryIncomplete finding says so.
+func (s *Service) ReviewDiff(ctx context.Context, gitDiff string) ([]review.Finding, error) {
+	annotated := review.Annotate(gitDiff)
+	annotated, truncated := truncate(annotated, maxPromptInput, "diff")
+	prompt := prompts.ReviewPrompt(annotated)
+	opts := append(s.callOptions(), llms.WithJSONMode())
+
+	response, err := s.generate(ctx, s.messages(prompt), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review changes: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from LLM")
+	}
+
+	var result struct {
+		Findings []struct {
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+			Severity string `json:"severity"`
+			Category string `json:"category"`
+			Message  string `json:"message"`
+		} `json:"findings"`
+	}
+	if err := decodeObject(response.Choices[0].Content, "review", &result); err != nil {
+		return nil, err
+	}
+
+	var findings []review.Finding
+	for _, f := range result.Findings {
+		message := strings.TrimSpace(f.Message)
+		if message == "" {
+			continue
+		}
+		severity, err := config.ParseSeverity(f.Severity)
+		if err != nil {
+			// An unknown severity is neither dropped nor allowed to block
+			severity = config.SeverityMedium
+		}
+		line := f.Line
+		if line < 0 {
+			line = 0
+		}
+		findings = append(findings, review.Finding{
+			File:     strings.TrimPrefix(strings.TrimPrefix(f.File, "b/"), "a/"),
+			Line:     line,
+			Severity: severity,
+			Category: strings.ToLower(strings.TrimSpace(f.Category)),
+			Message:  message,
+		})
+	}
+	if truncated {
+		findings = append(findings, review.Finding{
+			Severity: config.SeverityHigh,
+			Category: review.CategoryIncomplete,
+			Message:  fmt.Sprintf("The diff is larger than %d bytes; only its beginning was reviewed", maxPromptInput),
+		})
+	}
+	review.Sort(findings)
+	return findings, nil
+}
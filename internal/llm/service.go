@@ -2,10 +2,19 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bitcs/commet/internal/cache"
 	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/llm/fake"
+	"github.com/bitcs/commet/internal/message"
 	"github.com/bitcs/commet/internal/prompts"
+	"github.com/bitcs/commet/internal/usage"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/anthropic"
 	"github.com/tmc/langchaingo/llms/googleai"
@@ -15,59 +24,280 @@ import (
 type Service struct {
 	llm    llms.Model
 	config *config.Config
+	// fallbacks are tried in order when llm keeps failing
+	fallbacks []candidate
+	// cache stores generated messages; nil when caching is disabled
+	cache     *cache.Store
+	fromCache bool
+	// usage accumulates the tokens spent by this service
+	usage   usage.Usage
+	command string
+	repo    string
+	// ledger enables writing requests to the usage ledger
+	ledger bool
+}
+
+// candidate is a provider/model pair the service can generate with
+type candidate struct {
+	provider config.Provider
+	model    string
+	llm      llms.Model
+}
+
+func (c candidate) String() string {
+	return c.provider.String() + ":" + c.model
 }
 
 func NewService(cfg *config.Config) (*Service, error) {
-	llmModel, err := createLLM(cfg)
+	attempts := cfg.AI.MaxAttempts()
+
+	llmModel, err := createLLM(cfg.AI.Provider, cfg.AI.APIKey, resolveModel(cfg.AI.Provider, cfg.AI.Model), attempts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM: %w", err)
 	}
 
+	var fallbacks []candidate
+	for _, f := range cfg.AI.Fallbacks {
+		name := resolveModel(f.Provider, f.Model)
+		model, err := createLLM(f.Provider, cfg.AI.APIKeys[f.Provider.String()], name, attempts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create fallback LLM %s: %w", f, err)
+		}
+		fallbacks = append(fallbacks, candidate{provider: f.Provider, model: name, llm: model})
+	}
+
+	// Fixture runs are tests: they must not return messages cached from
+	// other fixtures or add fake spend to the user's ledger
+	fixtures := cfg.AI.Provider == config.ProviderFake && os.Getenv(fake.FixturesEnv) != ""
+
+	var store *cache.Store
+	if !fixtures {
+		// A broken cache directory only costs the cache, not the generation
+		store, _ = cache.Open()
+	}
+
 	return &Service{
-		llm:    llmModel,
-		config: cfg,
+		llm:       llmModel,
+		config:    cfg,
+		fallbacks: fallbacks,
+		cache:     store,
+		ledger:    !fixtures,
 	}, nil
 }
 
-func createLLM(cfg *config.Config) (llms.Model, error) {
-	model := cfg.AI.Model
+// DisableCache makes the service always call the provider
+func (s *Service) DisableCache() {
+	s.cache = nil
+}
+
+// SetOrigin labels the usage ledger entries written by this service with the
+// command and repository that caused them
+func (s *Service) SetOrigin(command, repo string) {
+	s.command = command
+	s.repo = repo
+}
+
+// Usage returns the tokens and cost spent by this service so far
+func (s *Service) Usage() usage.Usage {
+	return s.usage
+}
+
+// FromCache reports whether the last generated message came from the cache
+func (s *Service) FromCache() bool {
+	return s.fromCache
+}
+
+// cacheKey identifies a request by everything that influences its output
+func (s *Service) cacheKey(kind, prompt string) string {
+	ai := s.config.AI
+	model := ai.Model
 	if model == "" {
-		model = cfg.AI.GetDefaultModel()
+		model = ai.GetDefaultModel()
 	}
+	// A message written by a fallback is cached under the primary's key, so
+	// the chain is part of what produced it
+	fallbacks := make([]string, len(s.fallbacks))
+	for i, f := range s.fallbacks {
+		fallbacks[i] = f.String()
+	}
+	return cache.Key(
+		kind,
+		prompt,
+		ai.Provider.String(),
+		model,
+		ai.SystemPrompt,
+		config.FormatOptional(ai.Temperature),
+		config.FormatOptional(ai.TopP),
+		strconv.Itoa(ai.MaxTokens),
+		strconv.Itoa(ai.SubjectLimit()),
+		strings.Join(fallbacks, ","),
+	)
+}
 
-	switch cfg.AI.Provider {
+// resolveModel returns model, or the provider's default model when empty
+func resolveModel(provider config.Provider, model string) string {
+	if model == "" {
+		return (&config.AIConfig{Provider: provider}).GetDefaultModel()
+	}
+	return model
+}
+
+func createLLM(provider config.Provider, apiKey, model string, attempts int) (llms.Model, error) {
+	client := newRetryClient(attempts)
+
+	switch provider {
 	case config.ProviderOpenAI:
 		return openai.New(
-			openai.WithToken(cfg.AI.APIKey),
+			openai.WithToken(apiKey),
 			openai.WithModel(model),
+			openai.WithHTTPClient(client),
 		)
 	case config.ProviderClaude:
 		return anthropic.New(
-			anthropic.WithToken(cfg.AI.APIKey),
+			anthropic.WithToken(apiKey),
 			anthropic.WithModel(model),
+			anthropic.WithHTTPClient(client),
 		)
 	case config.ProviderGoogle:
+		// The Google client speaks gRPC, so retries happen in generate
 		return googleai.New(context.Background(),
-			googleai.WithAPIKey(cfg.AI.APIKey),
+			googleai.WithAPIKey(apiKey),
 			googleai.WithDefaultModel(model),
 		)
 	case config.ProviderGroq:
 		return openai.New(
-			openai.WithToken(cfg.AI.APIKey),
+			openai.WithToken(apiKey),
 			openai.WithModel(model),
 			openai.WithBaseURL("https://api.groq.com/openai/v1"),
+			openai.WithHTTPClient(client),
 		)
+	case config.ProviderFake:
+		return fake.FromEnv()
 	default:
-		return nil, fmt.Errorf("unsupported provider: %s", cfg.AI.Provider)
+		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
 }
 
-func (s *Service) GenerateCommitMessage(ctx context.Context, gitDiff string) (string, error) {
-	prompt := prompts.CommitMessagePrompt(gitDiff)
+// generate sends the request to the primary model and then to each fallback
+// until one succeeds. Each provider gets a full request timeout of its own,
+// so a primary that keeps being rate limited still leaves time for the
+// fallbacks; cancelling ctx stops the whole chain.
+func (s *Service) generate(ctx context.Context, msgs []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
+	primary := candidate{
+		provider: s.config.AI.Provider,
+		model:    resolveModel(s.config.AI.Provider, s.config.AI.Model),
+		llm:      s.llm,
+	}
+	candidates := append([]candidate{primary}, s.fallbacks...)
 
-	response, err := s.llm.GenerateContent(ctx, []llms.MessageContent{
-		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	var lastErr error
+	for i, c := range candidates {
+		if i > 0 {
+			fmt.Fprintf(os.Stderr, "\n%s failed (%v), falling back to %s\n", candidates[i-1], lastErr, c)
+		}
+
+		attemptCtx, cancel := s.attemptContext(ctx)
+		var response *llms.ContentResponse
+		lastErr = withRetry(attemptCtx, s.config.AI.MaxAttempts(), func() error {
+			var err error
+			response, err = c.llm.GenerateContent(attemptCtx, msgs, opts...)
+			return err
+		})
+		cancel()
+		if lastErr == nil {
+			s.recordUsage(c, response)
+			return response, nil
+		}
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+// attemptContext derives the context for one provider: it has its own
+// request timeout but is still cancelled when ctx is cancelled
+func (s *Service) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	attempt, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.config.AI.RequestTimeout())
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			cancel()
+		}
 	})
+	return attempt, func() {
+		stop()
+		cancel()
+	}
+}
+
+// recordUsage adds the tokens of response to the service total and the
+// usage ledger
+func (s *Service) recordUsage(c candidate, response *llms.ContentResponse) {
+	var u usage.Usage
+	for _, choice := range response.Choices {
+		u.Add(usage.FromGenerationInfo(choice.GenerationInfo))
+	}
+	u = usage.WithCost(&s.config.AI, c.model, u)
+	s.usage.Add(u)
+	if !s.ledger {
+		return
+	}
+
+	// The ledger is informational; failing to write it must not fail the request
+	_ = usage.Append(usage.Record{
+		Time:         time.Now(),
+		Command:      s.command,
+		Repo:         s.repo,
+		Provider:     c.provider.String(),
+		Model:        c.model,
+		InputTokens:  u.InputTokens,
+		OutputTokens: u.OutputTokens,
+		Cost:         u.Cost,
+		Priced:       u.Priced,
+	})
+}
+
+// messages builds the request messages, prefixing the configured system prompt
+func (s *Service) messages(prompt string) []llms.MessageContent {
+	var msgs []llms.MessageContent
+	if s.config.AI.SystemPrompt != "" {
+		msgs = append(msgs, llms.TextParts(llms.ChatMessageTypeSystem, s.config.AI.SystemPrompt))
+	}
+	return append(msgs, llms.TextParts(llms.ChatMessageTypeHuman, prompt))
+}
+
+// callOptions translates the configured generation parameters
+func (s *Service) callOptions() []llms.CallOption {
+	var opts []llms.CallOption
+	if s.config.AI.Temperature != nil {
+		opts = append(opts, llms.WithTemperature(*s.config.AI.Temperature))
+	}
+	if s.config.AI.TopP != nil {
+		opts = append(opts, llms.WithTopP(*s.config.AI.TopP))
+	}
+	if s.config.AI.MaxTokens > 0 {
+		opts = append(opts, llms.WithMaxTokens(s.config.AI.MaxTokens))
+	}
+	return opts
+}
+
+func (s *Service) GenerateCommitMessage(ctx context.Context, gitDiff string) (string, error) {
+	prompt := prompts.CommitMessagePrompt(gitDiff, s.config.AI.Style)
+
+	s.fromCache = false
+	key := s.cacheKey("commit", prompt)
+	if s.cache != nil {
+		if cached, ok := s.cache.Get(key); ok {
+			s.fromCache = true
+			return cached, nil
+		}
+	}
+
+	// JSON mode is honored by OpenAI, Groq and Google and ignored by Claude,
+	// which follows the JSON instructions in the prompt instead
+	opts := append(s.callOptions(), llms.WithJSONMode())
+
+	response, err := s.generate(ctx, s.messages(prompt), opts...)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate commit message: %w", err)
 	}
@@ -76,7 +306,63 @@ func (s *Service) GenerateCommitMessage(ctx context.Context, gitDiff string) (st
 		return "", fmt.Errorf("no response from LLM")
 	}
 
-	return response.Choices[0].Content, nil
+	commitMsg, err := s.renderCommitMessage(response.Choices[0].Content)
+	if err != nil {
+		return "", err
+	}
+
+	commitMsg = s.enforceSubjectLength(ctx, commitMsg)
+	if s.cache != nil {
+		// Failing to cache must not fail the commit
+		_ = s.cache.Put(key, commitMsg)
+	}
+
+	return commitMsg, nil
+}
+
+// renderCommitMessage turns a JSON mode response into a commit message in the
+// configured style. Free text is accepted only when the model ignored the
+// JSON format entirely; a JSON object that fails to parse is an error, so its
+// raw text never ends up in a commit.
+func (s *Service) renderCommitMessage(content string) (string, error) {
+	var commitMsg string
+	structured, err := message.ParseJSON(content)
+	switch {
+	case err == nil:
+		commitMsg = NormalizeCommitMessage(structured.Render(s.config.AI.Style))
+	case errors.Is(err, message.ErrNoJSON):
+		commitMsg = NormalizeCommitMessage(content)
+	default:
+		return "", fmt.Errorf("LLM returned an invalid commit message: %w", err)
+	}
+	if commitMsg == "" {
+		return "", fmt.Errorf("LLM returned an empty commit message")
+	}
+	return commitMsg, nil
+}
+
+// enforceSubjectLength asks the model once to shorten an overlong subject and
+// truncates it at a word boundary if the answer still does not fit
+func (s *Service) enforceSubjectLength(ctx context.Context, message string) string {
+	limit := s.config.AI.SubjectLimit()
+	subject, body := SplitSubject(message)
+	if len([]rune(subject)) <= limit {
+		return message
+	}
+
+	prompt := prompts.ShortenSubjectPrompt(subject, limit)
+	response, err := s.generate(ctx, s.messages(prompt), s.callOptions()...)
+	if err == nil && len(response.Choices) > 0 {
+		if shorter, _ := SplitSubject(NormalizeCommitMessage(response.Choices[0].Content)); shorter != "" {
+			subject = shorter
+		}
+	}
+	subject = TruncateSubject(subject, limit)
+
+	if body == "" {
+		return subject
+	}
+	return subject + "\n\n" + body
 }
 
 
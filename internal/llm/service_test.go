@@ -0,0 +1,257 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/llm/fake"
+	"github.com/bitcs/commet/internal/review"
+	"github.com/tmc/langchaingo/llms"
+)
+
+const readmeDiff = `diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1,2 @@
+ # Commet
++Works offline.
+`
+
+func testConfig() *config.Config {
+	return &config.Config{AI: config.AIConfig{Provider: config.ProviderFake, Style: config.StyleConventional}}
+}
+
+// newTestService returns a Service generating with model, without cache or
+// usage ledger
+func newTestService(t *testing.T, model llms.Model) *Service {
+	t.Helper()
+	return &Service{llm: model, config: testConfig()}
+}
+
+func loadFixtures(t *testing.T) *fake.Model {
+	t.Helper()
+	model, err := fake.Load(filepath.Join("fake", "testdata", "fixtures.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+func TestGenerateCommitMessageFromFixtures(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    string
+		want    string
+		wantErr string
+	}{
+		{name: "matching fixture", diff: readmeDiff, want: "docs(readme): describe the fake provider"},
+		{name: "default response", diff: "diff --git a/main.go b/main.go\n+x\n", want: "chore: update 1 file(s)"},
+		{name: "fixture error", diff: "diff --git a/broken b/broken\n+x\n", wantErr: "fake provider error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, loadFixtures(t))
+			got, err := s.GenerateCommitMessage(context.Background(), tt.diff)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCommitMessageUsesJSONModeAndCountsUsage(t *testing.T) {
+	model := loadFixtures(t)
+	s := newTestService(t, model)
+
+	if _, err := s.GenerateCommitMessage(context.Background(), readmeDiff); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := model.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("calls = %d, want 1", len(calls))
+	}
+	if !calls[0].Options.JSONMode {
+		t.Error("commit message request did not use JSON mode")
+	}
+	if !strings.Contains(calls[0].Prompt, "+Works offline.") {
+		t.Error("prompt does not contain the diff")
+	}
+	if u := s.Usage(); u.InputTokens == 0 || u.OutputTokens == 0 {
+		t.Errorf("usage = %+v, want tokens counted", u)
+	}
+}
+
+func TestEnforceSubjectLengthAsksForShorterSubject(t *testing.T) {
+	model := loadFixtures(t)
+	s := newTestService(t, model)
+	s.config.AI.MaxSubjectLength = 24
+
+	got, err := s.GenerateCommitMessage(context.Background(), readmeDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "docs: shorten subject" {
+		t.Errorf("message = %q, want the shortened subject", got)
+	}
+	if n := len(model.Calls()); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+}
+
+func TestGenerateFallsBackToNextProvider(t *testing.T) {
+	primary, err := fake.WithFixtures([]fake.Fixture{{Error: "primary down"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallback := fake.New()
+
+	s := newTestService(t, primary)
+	s.fallbacks = []candidate{{provider: config.ProviderFake, model: "fallback", llm: fallback}}
+
+	got, err := s.GenerateCommitMessage(context.Background(), readmeDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "chore: update 1 file(s)" {
+		t.Errorf("message = %q, want the fallback's response", got)
+	}
+	if len(primary.Calls()) != 1 || len(fallback.Calls()) != 1 {
+		t.Errorf("calls = %d primary, %d fallback; want 1 each", len(primary.Calls()), len(fallback.Calls()))
+	}
+}
+
+func TestNewServiceWithFixturesSkipsCacheAndLedger(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv(fake.FixturesEnv, filepath.Join("fake", "testdata", "fixtures.json"))
+
+	s, err := NewService(testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.cache != nil {
+		t.Error("fixture runs must not use the response cache")
+	}
+
+	if _, err := s.GenerateCommitMessage(context.Background(), readmeDiff); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "state", "commet", "usage.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("usage ledger written for a fixture run (stat error: %v)", err)
+	}
+}
+
+func TestReviewDiffFlagsTruncatedDiff(t *testing.T) {
+	model, err := fake.WithFixtures([]fake.Fixture{{Response: `{"findings": []}`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestService(t, model)
+
+	findings, err := s.ReviewDiff(context.Background(), readmeDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if review.Incomplete(findings) {
+		t.Errorf("small diff reported as incomplete: %+v", findings)
+	}
+
+	large := readmeDiff + strings.Repeat("+filler line\n", maxPromptInput/10)
+	findings, err = s.ReviewDiff(context.Background(), large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !review.Incomplete(findings) || findings[0].Severity != config.SeverityHigh {
+		t.Errorf("findings = %+v, want a high severity incomplete finding", findings)
+	}
+}
+
+func TestGenerateCommitMessageRejectsInvalidJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  string
+	}{
+		{name: "free text", response: "fix(parser): handle empty input", want: "fix(parser): handle empty input"},
+		{name: "empty subject", response: `{"type": "feat", "subject": ""}`, wantErr: "invalid commit message"},
+		{name: "schema mismatch", response: `{"type": "feat", "subject": ["a", "b"]}`, wantErr: "invalid commit message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := fake.WithFixtures([]fake.Fixture{{Response: tt.response}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := newTestService(t, model)
+
+			for name, generate := range map[string]func() (string, error){
+				"commit": func() (string, error) { return s.GenerateCommitMessage(context.Background(), readmeDiff) },
+				"squash": func() (string, error) { return s.GenerateSquashMessage(context.Background(), "- wip", readmeDiff) },
+			} {
+				got, err := generate()
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("%s: message = %q, error = %v, want %q", name, got, err, tt.wantErr)
+					}
+					continue
+				}
+				if err != nil || got != tt.want {
+					t.Errorf("%s: message = %q, %v, want %q", name, got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTriesFallbacksInOrder(t *testing.T) {
+	failing := func() *fake.Model {
+		m, err := fake.WithFixtures([]fake.Fixture{{Error: "down"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	answering := func(subject string) *fake.Model {
+		m, err := fake.WithFixtures([]fake.Fixture{{Response: `{"type": "fix", "subject": "` + subject + `"}`}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+
+	primary, first, second, third := failing(), failing(), answering("from second"), answering("from third")
+	s := newTestService(t, primary)
+	s.fallbacks = []candidate{
+		{provider: config.ProviderFake, model: "first", llm: first},
+		{provider: config.ProviderFake, model: "second", llm: second},
+		{provider: config.ProviderFake, model: "third", llm: third},
+	}
+
+	got, err := s.GenerateCommitMessage(context.Background(), readmeDiff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "fix: from second" {
+		t.Errorf("message = %q, want the second fallback's", got)
+	}
+	calls := []int{len(primary.Calls()), len(first.Calls()), len(second.Calls()), len(third.Calls())}
+	if calls[0] != 1 || calls[1] != 1 || calls[2] != 1 || calls[3] != 0 {
+		t.Errorf("calls = %v, want [1 1 1 0]", calls)
+	}
+}
@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bitcs/commet/internal/message"
+	"github.com/bitcs/commet/internal/prompts"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// PlannedCommit is one commit of a split plan proposed by the model
+type PlannedCommit struct {
+	Message string
+	// Changes are the IDs of the changes the commit should contain
+	Changes []string
+}
+
+// GenerateSplitPlan asks the model to group the described changes into
+// several commits
+func (s *Service) GenerateSplitPlan(ctx context.Context, changes string) ([]PlannedCommit, error) {
+	prompt := prompts.SplitPrompt(changes, s.config.AI.Style)
+	opts := append(s.callOptions(), llms.WithJSONMode())
+
+	response, err := s.generate(ctx, s.messages(prompt), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate split plan: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from LLM")
+	}
+
+	var plan struct {
+		Commits []json.RawMessage `json:"commits"`
+	}
+	if err := decodeObject(response.Choices[0].Content, "split plan", &plan); err != nil {
+		return nil, err
+	}
+
+	limit := s.config.AI.SubjectLimit()
+	var planned []PlannedCommit
+	for _, raw := range plan.Commits {
+		var ids struct {
+			Changes []string `json:"changes"`
+		}
+		if err := json.Unmarshal(raw, &ids); err != nil || len(ids.Changes) == 0 {
+			continue
+		}
+		msg, err := message.ParseJSON(string(raw))
+		if err != nil {
+			continue
+		}
+
+		subject, body := SplitSubject(NormalizeCommitMessage(msg.Render(s.config.AI.Style)))
+		text := TruncateSubject(subject, limit)
+		if body != "" {
+			text += "\n\n" + body
+		}
+		planned = append(planned, PlannedCommit{Message: text, Changes: ids.Changes})
+	}
+
+	if len(planned) == 0 {
+		return nil, fmt.Errorf("split plan contains no commits")
+	}
+	return planned, nil
+}
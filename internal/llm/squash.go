@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcs/commet/internal/prompts"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// GenerateSquashMessage writes one commit message for a series of commits
+// from their messages and combined diff
+func (s *Service) GenerateSquashMessage(ctx context.Context, messages, gitDiff string) (string, error) {
+	gitDiff, _ = truncate(gitDiff, maxPromptInput, "diff")
+	prompt := prompts.SquashPrompt(messages, gitDiff, s.config.AI.Style)
+	opts := append(s.callOptions(), llms.WithJSONMode())
+
+	response, err := s.generate(ctx, s.messages(prompt), opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate squash message: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+
+	commitMsg, err := s.renderCommitMessage(response.Choices[0].Content)
+	if err != nil {
+		return "", err
+	}
+	return s.enforceSubjectLength(ctx, commitMsg), nil
+}
@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bitcs/commet/internal/message"
+)
+
+// maxPromptInput bounds the diffs, histories and commit lists sent in one
+// prompt; commit messages carry the intent of whatever is cut off
+const maxPromptInput = 100_000
+
+// truncate cuts text to at most limit bytes without splitting a rune and
+// marks the cut with what was shortened. It reports whether text was cut.
+func truncate(text string, limit int, what string) (string, bool) {
+	if len(text) <= limit {
+		return text, false
+	}
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit] + "\n[" + what + " truncated]", true
+}
+
+// decodeObject unmarshals the JSON object in a model response into v,
+// ignoring any text or code fence around it. name describes the response in
+// errors.
+func decodeObject(content, name string, v any) error {
+	raw, ok := message.ExtractObject(content)
+	if !ok {
+		return fmt.Errorf("no JSON object in %s", name)
+	}
+	if err := json.Unmarshal([]byte(raw), v); err != nil {
+		return fmt.Errorf("invalid %s JSON: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+		cut   bool
+	}{
+		{"fits", "abc", 3, "abc", false},
+		{"ascii", "abcdef", 3, "abc\n[diff truncated]", true},
+		{"keeps whole runes", "aé€", 4, "aé\n[diff truncated]", true},
+		{"cut inside first rune", "€uro", 2, "\n[diff truncated]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cut := truncate(tt.text, tt.limit, "diff")
+			if got != tt.want || cut != tt.cut {
+				t.Errorf("truncate(%q, %d) = %q, %v, want %q, %v", tt.text, tt.limit, got, cut, tt.want, tt.cut)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) split a rune: %q", tt.text, tt.limit, got)
+			}
+		})
+	}
+}
+
+func TestDecodeObject(t *testing.T) {
+	var v struct {
+		Title string `json:"title"`
+	}
+	if err := decodeObject("Sure:\n```json\n{\"title\": \"x {y}\"}\n```", "pull request", &v); err != nil || v.Title != "x {y}" {
+		t.Errorf("decodeObject() = %+v, %v", v, err)
+	}
+
+	err := decodeObject("no json here", "pull request", &v)
+	if err == nil || !strings.Contains(err.Error(), "no JSON object in pull request") {
+		t.Errorf("decodeObject() without JSON error = %v", err)
+	}
+	err = decodeObject("{\"title\": 1}", "pull request", &v)
+	if err == nil || !strings.Contains(err.Error(), "invalid pull request JSON") {
+		t.Errorf("decodeObject() with a wrong type error = %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// Types lists the conventional commit types the model may choose from
+var Types = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// CommitMessage is a structured commit message as returned by the model
+type CommitMessage struct {
+	Type     string   `json:"type"`
+	Scope    string   `json:"scope"`
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body"`
+	Breaking string   `json:"breaking_change"`
+	Footers  []string `json:"footers"`
+}
+
+// ErrNoJSON is returned by ParseJSON when the response holds no JSON object
+var ErrNoJSON = errors.New("no JSON object in response")
+
+var headerPattern = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// ExtractObject returns the JSON object in a model response, from its first
+// opening to its last closing brace, ignoring any text or code fence around it
+func ExtractObject(text string) (string, bool) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return "", false
+	}
+	return text[start : end+1], true
+}
+
+// ParseJSON extracts a CommitMessage from a model response, tolerating text
+// or code fences around the JSON object
+func ParseJSON(text string) (*CommitMessage, error) {
+	raw, ok := ExtractObject(text)
+	if !ok {
+		return nil, ErrNoJSON
+	}
+
+	var msg CommitMessage
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		return nil, fmt.Errorf("invalid commit message JSON: %w", err)
+	}
+	if err := msg.normalize(); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// ParseConventional parses a plain text message with a conventional commit
+// header. It reports false when the header does not follow the convention.
+func ParseConventional(text string) (CommitMessage, bool) {
+	header, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	m := headerPattern.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return CommitMessage{Subject: strings.TrimSpace(header), Body: strings.TrimSpace(rest)}, false
+	}
+
+	msg := CommitMessage{
+		Type:    strings.ToLower(m[1]),
+		Scope:   m[2],
+		Subject: m[4],
+	}
+
+	var body []string
+	for _, line := range strings.Split(strings.TrimSpace(rest), "\n") {
+		if note, ok := strings.CutPrefix(line, "BREAKING CHANGE: "); ok {
+			msg.Breaking = note
+			continue
+		}
+		if note, ok := strings.CutPrefix(line, "BREAKING-CHANGE: "); ok {
+			msg.Breaking = note
+			continue
+		}
+		body = append(body, line)
+	}
+	msg.Body = strings.TrimSpace(strings.Join(body, "\n"))
+	if m[3] == "!" && msg.Breaking == "" {
+		msg.Breaking = msg.Subject
+	}
+	return msg, true
+}
+
+// IsBreaking reports whether the message announces a breaking change
+func (m CommitMessage) IsBreaking() bool {
+	return m.Breaking != ""
+}
+
+// Header returns the first line of the message for the given style
+func (m CommitMessage) Header(style config.Style) string {
+	if style == config.StyleSimple || m.Type == "" {
+		return Capitalize(m.Subject)
+	}
+
+	header := m.Type
+	if m.Scope != "" {
+		header += "(" + m.Scope + ")"
+	}
+	if m.IsBreaking() {
+		header += "!"
+	}
+	return header + ": " + m.Subject
+}
+
+// Render formats the message for the given style. The simple style produces a
+// single line; the others add the body, breaking change note and footers.
+func (m CommitMessage) Render(style config.Style) string {
+	header := m.Header(style)
+	if style == config.StyleSimple {
+		return header
+	}
+
+	var footers []string
+	if m.IsBreaking() {
+		footers = append(footers, "BREAKING CHANGE: "+m.Breaking)
+	}
+	footers = append(footers, m.Footers...)
+
+	parts := []string{header}
+	if m.Body != "" {
+		parts = append(parts, m.Body)
+	}
+	if len(footers) > 0 {
+		parts = append(parts, strings.Join(footers, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// normalize validates the fields and fixes common formatting slips
+func (m *CommitMessage) normalize() error {
+	m.Type = strings.ToLower(strings.TrimSpace(m.Type))
+	m.Scope = strings.TrimSpace(m.Scope)
+	m.Subject = strings.TrimSuffix(strings.TrimSpace(m.Subject), ".")
+	m.Body = strings.TrimSpace(m.Body)
+	m.Breaking = strings.TrimSpace(m.Breaking)
+
+	footers := m.Footers[:0]
+	for _, f := range m.Footers {
+		if f = strings.TrimSpace(f); f != "" {
+			footers = append(footers, f)
+		}
+	}
+	m.Footers = footers
+
+	if m.Subject == "" {
+		return fmt.Errorf("commit message JSON has no subject")
+	}
+	if m.Type != "" && !isKnownType(m.Type) {
+		// An invented type is worse than none; keep the rest of the message
+		m.Type = ""
+		m.Scope = ""
+	}
+
+	// Models sometimes repeat the type prefix inside the subject
+	if parsed, ok := ParseConventional(m.Subject); ok && parsed.Type == m.Type {
+		m.Subject = parsed.Subject
+	}
+	if m.Type != "" {
+		m.Subject = lowerFirst(m.Subject)
+	}
+	return nil
+}
+
+func isKnownType(t string) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Capitalize upper-cases the first letter of s
+func Capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// lowerFirst lowercases the first letter unless the first word looks like an
+// acronym or identifier (e.g. "API", "README")
+func lowerFirst(s string) string {
+	word, _, _ := strings.Cut(s, " ")
+	if len(word) > 1 && strings.ToUpper(word) == word {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToLower(r)) + s[size:]
+}
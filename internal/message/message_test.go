@@ -0,0 +1,43 @@
+package message
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractObject(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{`{"a": 1}`, `{"a": 1}`, true},
+		{"```json\n{\"a\": {\"b\": 2}}\n```", `{"a": {"b": 2}}`, true},
+		{`Here you go: {"a": 1} done`, `{"a": 1}`, true},
+		{"no object", "", false},
+		{"} backwards {", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ExtractObject(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ExtractObject(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	msg, err := ParseJSON("```json\n{\"type\": \"Feat\", \"subject\": \"add login.\", \"footers\": [\" \", \"Refs: #1\"]}\n```")
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+	if msg.Type != "feat" || msg.Subject != "add login" || len(msg.Footers) != 1 {
+		t.Errorf("ParseJSON() = %+v", msg)
+	}
+
+	if _, err := ParseJSON("feat: add login"); !errors.Is(err, ErrNoJSON) {
+		t.Errorf("ParseJSON() of free text error = %v, want ErrNoJSON", err)
+	}
+	if _, err := ParseJSON(`{"type": "feat", "subject": ""}`); err == nil || errors.Is(err, ErrNoJSON) {
+		t.Errorf("ParseJSON() of an empty subject error = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,38 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClassifyCommitsPrompt asks the model to sort commit messages into Keep a
+// Changelog categories and to rewrite them as changelog entries
+func ClassifyCommitsPrompt(messages []string) string {
+	var list strings.Builder
+	for i, m := range messages {
+		fmt.Fprintf(&list, "%d. %s\n", i+1, strings.ReplaceAll(strings.TrimSpace(m), "\n", "\n   "))
+	}
+
+	return fmt.Sprintf(`You are maintaining a project's changelog in the Keep a Changelog format.
+
+Classify each commit below into one of these sections:
+- Added: new features
+- Changed: changes in existing functionality, performance improvements, refactors users notice
+- Deprecated: soon-to-be removed features
+- Removed: removed features
+- Fixed: bug fixes
+- Security: vulnerability fixes
+- Skip: internal changes users do not care about (tests, CI, build, formatting, chores, merges)
+
+For every commit also write a short changelog entry: one line, imperative or descriptive, understandable by users, without a trailing period.
+Set "breaking" when the commit breaks backwards compatibility.
+
+Commits:
+%s
+Return ONLY a JSON object with one item per commit, in the same order, and no other text:
+{
+  "commits": [
+    {"id": 1, "section": "Added", "entry": "Support exporting reports as CSV", "breaking": false}
+  ]
+}`, list.String())
+}
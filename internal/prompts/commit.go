@@ -1,16 +1,55 @@
 package prompts
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/message"
+)
+
+// styleGuidelines describes the expected message shape for each commit style
+var styleGuidelines = map[config.Style]string{
+	config.StyleConventional: "- Set \"type\" to the conventional commit type (%s) and \"scope\" to the affected component when there is a clear one",
+	config.StyleSimple:       "- Leave \"type\", \"scope\" and \"body\" empty; only write a plain subject line",
+	config.StyleDetailed:     "- Set \"type\" to the conventional commit type (%s) and always fill \"body\" with the motivation and the main changes as short bullet points",
+}
+
+// commitMessageFormat asks for the JSON fields of message.CommitMessage
+const commitMessageFormat = `Return ONLY a JSON object with these fields and no other text:
+{
+  "type": "conventional commit type or empty",
+  "scope": "affected component or empty",
+  "subject": "short imperative summary",
+  "body": "optional explanation, lines wrapped at 72 characters",
+  "breaking_change": "description of the breaking change, or empty if none",
+  "footers": ["optional trailers such as Refs: #123"]
+}`
+
+// styleGuideline returns the guideline for style, defaulting to the
+// conventional style, with the allowed commit types filled in
+func styleGuideline(style config.Style) string {
+	guideline, ok := styleGuidelines[style]
+	if !ok {
+		guideline = styleGuidelines[config.StyleConventional]
+	}
+	if strings.Contains(guideline, "%s") {
+		guideline = fmt.Sprintf(guideline, strings.Join(message.Types, ", "))
+	}
+	return guideline
+}
 
 // CommitMessagePrompt generates a prompt for creating commit messages based on git diff
-func CommitMessagePrompt(gitDiff string) string {
+func CommitMessagePrompt(gitDiff string, style config.Style) string {
+	guideline := styleGuideline(style)
+
 	return fmt.Sprintf(`You are an expert software engineer with years of experience writing clear, professional commit messages that follow industry best practices.
 
 Analyze the following git diff and generate a commit message that:
 
 **Format Requirements:**
-- Subject line: 50 characters or less, imperative mood (e.g., "Add", "Fix", "Update", "Remove")
-- Use conventional commit format when appropriate (feat:, fix:, docs:, refactor:, etc.)
+- Subject: 50 characters or less without the type prefix, imperative mood (e.g., "add", "fix", "update", "remove")
+%s
 - If the change is complex, include a brief body (optional, max 72 chars per line)
 
 **Content Guidelines:**
@@ -28,5 +67,17 @@ Analyze the following git diff and generate a commit message that:
 Git diff:
 %s
 
-Return ONLY the commit message (subject line + optional body if needed). No explanations, comments, or additional text.`, gitDiff)
-}
\ No newline at end of file
+%s`, guideline, gitDiff, commitMessageFormat)
+}
+
+// ShortenSubjectPrompt asks the model to rewrite a commit subject that exceeds
+// the configured length limit
+func ShortenSubjectPrompt(subject string, limit int) string {
+	return fmt.Sprintf(`Rewrite this git commit subject line so it is at most %d characters long.
+Keep the conventional commit type and scope prefix if present, the imperative mood and the key meaning.
+
+Subject:
+%s
+
+Return ONLY the rewritten subject line.`, limit, subject)
+}
@@ -0,0 +1,64 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Depth controls how detailed an explanation is
+type Depth string
+
+const (
+	DepthBrief    Depth = "brief"
+	DepthNormal   Depth = "normal"
+	DepthDetailed Depth = "detailed"
+)
+
+var depthGuidelines = map[Depth]string{
+	DepthBrief: `Write a short paragraph of at most four sentences covering what changed and why. No headings or lists.`,
+	DepthNormal: `Use these Markdown sections:
+### What changed
+A few bullet points describing the changes by behavior, not by file
+### Why
+The motivation, as far as the commit messages and code reveal it
+### Impact
+Who or what is affected and anything risky to keep in mind`,
+	DepthDetailed: `Use these Markdown sections:
+### Summary
+Two or three sentences for someone new to the code base
+### What changed
+A walkthrough of the changes grouped by component, naming the important files, functions and types
+### Why
+The motivation and the design decisions visible in the code, including alternatives the change rules out
+### Impact
+Behavior changes, compatibility, performance and risks
+### Where to look
+The files and functions to read first to understand the change`,
+}
+
+// ParseDepth accepts brief, normal or detailed
+func ParseDepth(s string) (Depth, error) {
+	depth := Depth(strings.ToLower(s))
+	if _, ok := depthGuidelines[depth]; !ok {
+		return "", fmt.Errorf("invalid depth: %s (valid options: brief, normal, detailed)", s)
+	}
+	return depth, nil
+}
+
+// ExplainPrompt asks for a plain-language explanation of the git show or log
+// output of a commit or range
+func ExplainPrompt(spec, history string, depth Depth) string {
+	return fmt.Sprintf(`You are a senior engineer explaining past changes to a teammate who is new to this code base.
+
+Explain in plain language what %s changed and why:
+- Base the explanation on the commit messages and the patches; say so when the reason is not evident instead of guessing
+- Explain jargon and project-specific names the first time they appear
+- Do not restate the diff line by line
+
+%s
+
+Commits and patches:
+%s
+
+Return ONLY the explanation in Markdown, with no other text.`, spec, depthGuidelines[depth], history)
+}
@@ -0,0 +1,38 @@
+package prompts
+
+import "fmt"
+
+// PullRequestPrompt asks for a pull request title and Markdown description
+// based on the branch's commits and diff, following the repository's
+// template when there is one
+func PullRequestPrompt(commits, gitDiff, template string) string {
+	structure := `Use these Markdown sections in the body:
+## Summary
+## Changes
+## Testing`
+	if template != "" {
+		structure = fmt.Sprintf(`Fill in this pull request template from the repository, keeping its headings and checklists:
+%s`, template)
+	}
+
+	return fmt.Sprintf(`You are an expert software engineer writing a pull request for review.
+
+Write a pull request title and description for the branch below:
+- Title: 72 characters or less, imperative mood, summarizing the whole branch
+- Body: explain what changed and why for a reviewer with no context; mention anything they should look at closely
+- Base the description on the commits and the diff; do not invent tests, issues or links
+
+%s
+
+Commits on the branch (oldest first):
+%s
+
+Diff against the base branch:
+%s
+
+Return ONLY a JSON object with these fields and no other text:
+{
+  "title": "pull request title",
+  "body": "Markdown description"
+}`, structure, commits, gitDiff)
+}
@@ -0,0 +1,22 @@
+package prompts
+
+import "fmt"
+
+// ReleaseNotesPrompt asks for release notes for version based on the
+// classified changes and the commit messages since the previous release
+func ReleaseNotesPrompt(version, changes, commits string) string {
+	return fmt.Sprintf(`You are a maintainer writing the release notes for version %s of a project.
+
+Write release notes for users upgrading to this version:
+- Start with one or two sentences summarizing the release
+- Follow with Markdown sections (### Breaking Changes, ### Features, ### Fixes, ### Other) and omit empty sections
+- Describe each change from the user's point of view in one line; merge related commits
+- List breaking changes first and explain what users need to change
+- Do not include a title with the version, and do not invent changes, issues or links
+
+Changes, categorized:
+%s
+Commit messages (oldest first):
+%s
+Return ONLY the release notes in Markdown, with no other text.`, version, changes, commits)
+}
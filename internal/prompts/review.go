@@ -0,0 +1,32 @@
+package prompts
+
+import "fmt"
+
+// ReviewPrompt asks for a code review of a diff whose new lines are prefixed
+// with their line numbers
+func ReviewPrompt(gitDiff string) string {
+	return fmt.Sprintf(`You are a senior software engineer reviewing a change before it is committed.
+
+Review the diff below and report real problems only:
+- Bugs, incorrect logic, unhandled errors, race conditions and resource leaks
+- Security issues such as injection, leaked secrets or missing validation
+- Performance problems and risky changes to public behavior
+- Leave out formatting nitpicks and matters of taste; an empty list is a fine answer
+
+Lines of the new file version are prefixed with their line number. For each finding give:
+- file: the path as shown in the diff (without a/ or b/)
+- line: the line number in the new file, or 0 when the finding is not about a single line
+- severity: one of info, low, medium, high, critical (high and critical must be fixed before committing)
+- category: one of bug, security, performance, error-handling, maintainability, style
+- message: what is wrong and how to fix it, in one or two sentences
+
+Diff:
+%s
+
+Return ONLY a JSON object with this format and no other text:
+{
+  "findings": [
+    {"file": "path/to/file.go", "line": 42, "severity": "high", "category": "bug", "message": "..."}
+  ]
+}`, gitDiff)
+}
@@ -0,0 +1,46 @@
+package prompts
+
+import (
+	"fmt"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// SplitPrompt asks the model to group the numbered changes of a working tree
+// into a sequence of logical commits
+func SplitPrompt(changes string, style config.Style) string {
+	guideline := styleGuideline(style)
+
+	return fmt.Sprintf(`You are an expert software engineer splitting a working tree full of unrelated edits into a clean series of commits.
+
+Below are the pending changes. Each one has an ID (C1, C2, ...) and is either a single diff hunk or a whole file.
+
+Group the changes into logical commits:
+- Every change ID must appear in exactly one commit
+- Changes that depend on each other belong in the same commit, or the dependency must come first
+- Keep unrelated concerns (features, fixes, refactors, docs, formatting) in separate commits
+- Prefer a few meaningful commits over many tiny ones
+- Order the commits so each one builds on the previous
+
+For each commit write a message:
+- Subject: 50 characters or less without the type prefix, imperative mood
+%s
+
+Changes:
+%s
+
+Return ONLY a JSON object with this shape and no other text:
+{
+  "commits": [
+    {
+      "type": "conventional commit type or empty",
+      "scope": "affected component or empty",
+      "subject": "short imperative summary",
+      "body": "optional explanation, lines wrapped at 72 characters",
+      "breaking_change": "description of the breaking change, or empty if none",
+      "footers": [],
+      "changes": ["C1", "C3"]
+    }
+  ]
+}`, guideline, changes)
+}
@@ -0,0 +1,30 @@
+package prompts
+
+import (
+	"fmt"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// SquashPrompt asks for one commit message replacing a series of commits,
+// based on their messages and their combined diff
+func SquashPrompt(messages, gitDiff string, style config.Style) string {
+	guideline := styleGuideline(style)
+
+	return fmt.Sprintf(`You are an expert software engineer squashing a branch's commits into a single commit.
+
+Write one commit message that describes the combined change:
+- Subject: 50 characters or less without the type prefix, imperative mood, covering the overall purpose
+%s
+- Use the individual messages for intent, but describe the end result of the combined diff; drop steps that were later reverted or fixed up
+- Ignore meaningless messages such as "wip" or "fix"; never list the original messages one after another
+- Keep trailers such as Refs:, Fixes: or Co-authored-by: from the original messages in "footers"
+
+Original commit messages (oldest first):
+%s
+
+Combined diff:
+%s
+
+%s`, guideline, messages, gitDiff, commitMessageFormat)
+}
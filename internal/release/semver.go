@@ -0,0 +1,179 @@
+// Package release works out semantic version bumps from commit history.
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bitcs/commet/internal/changelog"
+)
+
+// Version is a semantic version, optionally written with a "v" prefix
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+	Prefix              string
+}
+
+var semverPattern = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses a tag such as "v1.2.3" or "1.2.3-rc.1"
+func ParseVersion(s string) (Version, error) {
+	m := semverPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("%q is not a semantic version", s)
+	}
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	patch, _ := strconv.Atoi(m[4])
+	return Version{Major: major, Minor: minor, Patch: patch, Prerelease: m[5], Prefix: m[1]}, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Less orders versions by precedence; prereleases come before their release
+// and compare their dot-separated identifiers, numerically when both are
+// numbers
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	if v.Patch != o.Patch {
+		return v.Patch < o.Patch
+	}
+	if v.Prerelease == "" || o.Prerelease == "" {
+		return v.Prerelease != "" && o.Prerelease == ""
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease) < 0
+}
+
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			// Numeric identifiers sort before alphanumeric ones
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// Latest returns the highest release version among tags, ignoring
+// prereleases and tags that are not semantic versions
+func Latest(tags []string) (Version, string, bool) {
+	var (
+		latest Version
+		tag    string
+		found  bool
+	)
+	for _, t := range tags {
+		v, err := ParseVersion(t)
+		if err != nil || v.Prerelease != "" {
+			continue
+		}
+		if !found || latest.Less(v) {
+			latest, tag, found = v, t, true
+		}
+	}
+	return latest, tag, found
+}
+
+// Bump is the part of a version a release increments
+type Bump int
+
+const (
+	None Bump = iota
+	Patch
+	Minor
+	Major
+)
+
+func (b Bump) String() string {
+	switch b {
+	case Patch:
+		return "patch"
+	case Minor:
+		return "minor"
+	case Major:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// ParseBump accepts "major", "minor" or "patch"
+func ParseBump(s string) (Bump, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "major":
+		return Major, nil
+	case "minor":
+		return Minor, nil
+	case "patch":
+		return Patch, nil
+	default:
+		return None, fmt.Errorf("invalid bump %q (expected major, minor or patch)", s)
+	}
+}
+
+// Apply returns the version following v for bump b
+func (v Version) Apply(b Bump) Version {
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prefix: v.Prefix}
+	switch b {
+	case Major:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case Minor:
+		next.Minor, next.Patch = v.Minor+1, 0
+	case Patch:
+		next.Patch = v.Patch + 1
+	}
+	return next
+}
+
+// Required returns the bump the changelog entries call for: major for
+// breaking changes, minor for additions and patch for anything else
+// released
+func Required(entries []changelog.Entry) Bump {
+	bump := None
+	for _, e := range entries {
+		var b Bump
+		switch {
+		case e.Breaking:
+			b = Major
+		case e.Section == changelog.Added:
+			b = Minor
+		case e.Section != changelog.Skip:
+			b = Patch
+		}
+		if b > bump {
+			bump = b
+		}
+	}
+	return bump
+}
@@ -0,0 +1,155 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/bitcs/commet/internal/changelog"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}},
+		{"v0.10.0", Version{Minor: 10, Prefix: "v"}},
+		{"v2.0.0-rc.1", Version{Major: 2, Prerelease: "rc.1", Prefix: "v"}},
+		{"1.0.0-alpha+build.5", Version{Major: 1, Prerelease: "alpha"}},
+		{" v1.2.3\n", Version{Major: 1, Minor: 2, Patch: 3, Prefix: "v"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "1.2", "v1.2.3.4", "01.2.3", "release-1.2.3", "V1.2.3", "1.2.3-"} {
+		if got, err := ParseVersion(in); err == nil {
+			t.Errorf("ParseVersion(%q) = %+v, want error", in, got)
+		}
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	v := Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta.2", Prefix: "v"}
+	if got := v.String(); got != "v1.2.3-beta.2" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestLess(t *testing.T) {
+	// Each version has lower precedence than the next, per semver.org
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if !a.Less(b) {
+			t.Errorf("%s should be less than %s", ordered[i], ordered[i+1])
+		}
+		if b.Less(a) {
+			t.Errorf("%s should not be less than %s", ordered[i+1], ordered[i])
+		}
+	}
+
+	a, _ := ParseVersion("v1.0.0")
+	b, _ := ParseVersion("1.0.0")
+	if a.Less(b) || b.Less(a) {
+		t.Error("the v prefix should not affect precedence")
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    string
+		wantTag string
+		found   bool
+	}{
+		{"highest release", []string{"v1.2.0", "v1.10.0", "v1.9.3"}, "v1.10.0", "v1.10.0", true},
+		{"prereleases ignored", []string{"v1.0.0", "v2.0.0-rc.1"}, "v1.0.0", "v1.0.0", true},
+		{"non-semver ignored", []string{"nightly", "release-2", "0.3.1"}, "0.3.1", "0.3.1", true},
+		{"none", []string{"nightly", "v1.0.0-beta"}, "0.0.0", "", false},
+		{"empty", nil, "0.0.0", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, tag, found := Latest(tt.tags)
+			if got.String() != tt.want || tag != tt.wantTag || found != tt.found {
+				t.Errorf("Latest(%q) = %s, %q, %v, want %s, %q, %v", tt.tags, got, tag, found, tt.want, tt.wantTag, tt.found)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		from string
+		bump Bump
+		want string
+	}{
+		{"v1.2.3", Patch, "v1.2.4"},
+		{"v1.2.3", Minor, "v1.3.0"},
+		{"v1.2.3", Major, "v2.0.0"},
+		{"v1.2.3", None, "v1.2.3"},
+		{"0.4.2", Major, "1.0.0"},
+		{"v2.0.0-rc.1", Patch, "v2.0.1"},
+	}
+	for _, tt := range tests {
+		v, _ := ParseVersion(tt.from)
+		if got := v.Apply(tt.bump).String(); got != tt.want {
+			t.Errorf("%s.Apply(%s) = %s, want %s", tt.from, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestParseBump(t *testing.T) {
+	for in, want := range map[string]Bump{"major": Major, " Minor ": Minor, "PATCH": Patch} {
+		if got, err := ParseBump(in); err != nil || got != want {
+			t.Errorf("ParseBump(%q) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+	if _, err := ParseBump("huge"); err == nil {
+		t.Error("ParseBump(\"huge\") succeeded, want error")
+	}
+}
+
+func TestRequired(t *testing.T) {
+	fix := changelog.Entry{Section: changelog.Fixed}
+	feat := changelog.Entry{Section: changelog.Added}
+	breaking := changelog.Entry{Section: changelog.Changed, Breaking: true}
+	skipped := changelog.Entry{Section: changelog.Skip}
+
+	tests := []struct {
+		name    string
+		entries []changelog.Entry
+		want    Bump
+	}{
+		{"nothing", nil, None},
+		{"only skipped", []changelog.Entry{skipped}, None},
+		{"fix", []changelog.Entry{skipped, fix}, Patch},
+		{"feature", []changelog.Entry{fix, feat}, Minor},
+		{"breaking", []changelog.Entry{feat, breaking, fix}, Major},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Required(tt.entries); got != tt.want {
+				t.Errorf("Required() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
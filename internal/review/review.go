@@ -0,0 +1,179 @@
+// Package review holds AI code review findings and their output formats.
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/fatih/color"
+)
+
+// Finding is one issue reported by the review
+type Finding struct {
+	File     string          `json:"file"`
+	Line     int             `json:"line,omitempty"`
+	Severity config.Severity `json:"severity"`
+	// Category groups findings such as "bug", "security" or "style"
+	Category string `json:"category,omitempty"`
+	Message  string `json:"message"`
+}
+
+// CategoryIncomplete marks the finding added when the diff was too large to
+// be reviewed in full
+const CategoryIncomplete = "incomplete"
+
+// Location renders the finding's file and line as file:line
+func (f Finding) Location() string {
+	if f.Line > 0 {
+		return f.File + ":" + strconv.Itoa(f.Line)
+	}
+	return f.File
+}
+
+// Sort orders findings by descending severity, then by file and line
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() > b.Severity.Rank()
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
+
+// AtLeast returns the findings as severe as threshold
+func AtLeast(findings []Finding, threshold config.Severity) []Finding {
+	var result []Finding
+	for _, f := range findings {
+		if f.Severity.AtLeast(threshold) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// Incomplete reports whether the review only covered part of the diff
+func Incomplete(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Category == CategoryIncomplete {
+			return true
+		}
+	}
+	return false
+}
+
+var hunkPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// Annotate prefixes the added and context lines of a unified diff with their
+// line number in the new file, so the model can report accurate lines
+func Annotate(diff string) string {
+	var (
+		b      strings.Builder
+		line   int
+		inHunk bool
+	)
+	for _, l := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			inHunk = false
+		case hunkPattern.MatchString(l):
+			line, _ = strconv.Atoi(hunkPattern.FindStringSubmatch(l)[1])
+			inHunk = true
+		case inHunk && (strings.HasPrefix(l, "+") || strings.HasPrefix(l, " ")):
+			fmt.Fprintf(&b, "%5d %s\n", line, l)
+			line++
+			continue
+		case inHunk && strings.HasPrefix(l, "-"):
+			fmt.Fprintf(&b, "      %s\n", l)
+			continue
+		}
+		b.WriteString(l + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Format is an output format for findings
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatSARIF Format = "sarif"
+)
+
+// ParseFormat accepts text, json or sarif
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON, FormatSARIF:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid format: %s (valid options: text, json, sarif)", s)
+	}
+}
+
+// Write renders findings in format
+func Write(w io.Writer, format Format, findings []Finding) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, findings)
+	case FormatSARIF:
+		return writeSARIF(w, findings)
+	default:
+		writeText(w, findings)
+		return nil
+	}
+}
+
+var severityColors = map[config.Severity]*color.Color{
+	config.SeverityCritical: color.New(color.FgRed, color.Bold),
+	config.SeverityHigh:     color.New(color.FgRed),
+	config.SeverityMedium:   color.New(color.FgYellow),
+	config.SeverityLow:      color.New(color.FgCyan),
+	config.SeverityInfo:     color.New(color.FgWhite),
+}
+
+func writeText(w io.Writer, findings []Finding) {
+	if len(findings) == 0 {
+		color.New(color.FgGreen).Fprintln(w, "No issues found.")
+		return
+	}
+
+	counts := map[config.Severity]int{}
+	for _, f := range findings {
+		c, ok := severityColors[f.Severity]
+		if !ok {
+			c = severityColors[config.SeverityInfo]
+		}
+		c.Fprintf(w, "%-8s", f.Severity)
+		fmt.Fprintf(w, " %s\n", f.Location())
+		fmt.Fprintf(w, "         %s\n\n", strings.ReplaceAll(f.Message, "\n", "\n         "))
+		counts[f.Severity]++
+	}
+
+	var parts []string
+	severities := config.Severities()
+	for i := len(severities) - 1; i >= 0; i-- {
+		if n := counts[severities[i]]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, severities[i]))
+		}
+	}
+	fmt.Fprintf(w, "%d finding(s): %s\n", len(findings), strings.Join(parts, ", "))
+}
+
+func writeJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
@@ -0,0 +1,81 @@
+// Package reword finds commits whose messages are too poor to keep.
+package reword
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/bitcs/commet/internal/git"
+)
+
+// Candidate is a commit proposed for rewording
+type Candidate struct {
+	Commit git.Commit
+	// Reason explains why the message was flagged
+	Reason string
+	// Message is the proposed replacement
+	Message  string
+	Approved bool
+}
+
+var (
+	// placeholderSubjects are messages that say nothing about the change
+	placeholderSubjects = map[string]bool{
+		"wip": true, "fix": true, "fixes": true, "fixed": true, "fix bug": true, "fix bugs": true,
+		"bugfix": true, "small fix": true, "minor fix": true, "minor fixes": true, "quick fix": true,
+		"update": true, "updates": true, "updated": true, "change": true, "changes": true,
+		"stuff": true, "things": true, "misc": true, "minor": true, "tmp": true, "temp": true,
+		"test": true, "tests": true, "testing": true, "cleanup": true, "clean up": true,
+		"refactor": true, "more": true, "again": true, "oops": true, "typo": true,
+		"asdf": true, "foo": true, "save": true, "checkpoint": true, "commit": true,
+		"done": true, "work": true, "progress": true, "review": true, "address review": true,
+		"pr feedback": true, "address comments": true, "lint": true, "format": true,
+	}
+
+	// autosquashPattern matches subjects git creates for fixups
+	autosquashPattern = regexp.MustCompile(`^(fixup|squash|amend)! `)
+	wipPattern        = regexp.MustCompile(`(?i)^\W*wip\b`)
+)
+
+// minSubjectWords is the fewest words a descriptive subject has
+const minSubjectWords = 2
+
+// Assess returns why a commit message is too poor to keep, or an empty
+// string when it looks fine
+func Assess(message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	subject = strings.TrimSpace(subject)
+	normalized := strings.ToLower(strings.TrimRight(subject, ".!"))
+
+	switch {
+	case subject == "":
+		return "empty message"
+	case IsAutosquash(subject):
+		return ""
+	case wipPattern.MatchString(subject):
+		return "work in progress"
+	case placeholderSubjects[normalized]:
+		return "placeholder message"
+	case !strings.ContainsFunc(subject, unicode.IsLetter):
+		return "no words in message"
+	case len(strings.Fields(headerSubject(subject))) < minSubjectWords:
+		return "too short to describe the change"
+	}
+	return ""
+}
+
+// IsAutosquash reports whether a message marks a commit for
+// git rebase --autosquash; rewording it would lose that intent
+func IsAutosquash(message string) bool {
+	return autosquashPattern.MatchString(strings.TrimSpace(message))
+}
+
+// headerSubject strips a conventional commit prefix, so "fix: typo" is judged
+// by "typo"
+func headerSubject(subject string) string {
+	if i := strings.Index(subject, ": "); i >= 0 && !strings.Contains(subject[:i], " ") {
+		return subject[i+2:]
+	}
+	return subject
+}
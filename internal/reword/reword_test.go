@@ -0,0 +1,53 @@
+package reword
+
+import "testing"
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"", "empty message"},
+		{"   \n\n", "empty message"},
+		{"WIP", "work in progress"},
+		{"[wip] parser rewrite", "work in progress"},
+		{"fix", "placeholder message"},
+		{"Minor fixes.", "placeholder message"},
+		{"address review", "placeholder message"},
+		{"...", "no words in message"},
+		{"1234", "no words in message"},
+		{"parser", "too short to describe the change"},
+		{"fix: typo", "too short to describe the change"},
+		{"feat(ui): badges", "too short to describe the change"},
+
+		{"fix: handle empty input", ""},
+		{"Add retry to the uploader", ""},
+		{"wipe the cache on logout", ""},
+		{"Update README\n\nwip", ""},
+		{"fix: correct typo in docs\n\nBody text.", ""},
+		{"fixup! feat: add login", ""},
+		{"squash! fix", ""},
+		{"amend! wip", ""},
+	}
+	for _, tt := range tests {
+		if got := Assess(tt.message); got != tt.want {
+			t.Errorf("Assess(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestIsAutosquash(t *testing.T) {
+	tests := map[string]bool{
+		"fixup! feat: add login":   true,
+		"squash! fix the parser":   true,
+		"amend! docs: usage":       true,
+		"fixup!feat: add login":    false,
+		"feat: add fixup! support": false,
+		"Fixup the parser":         false,
+	}
+	for message, want := range tests {
+		if got := IsAutosquash(message); got != want {
+			t.Errorf("IsAutosquash(%q) = %v, want %v", message, got, want)
+		}
+	}
+}
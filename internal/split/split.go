@@ -0,0 +1,321 @@
+// Package split turns a working tree with mixed edits into a series of
+// commits: it collects the pending changes as hunks and whole files, arranges
+// them by a plan and stages and commits each group in turn.
+package split
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/message"
+)
+
+// maxPreviewLines bounds how much of a new file is shown to the model
+const maxPreviewLines = 200
+
+// Change is the unit a plan assigns to a commit: a hunk of a tracked file or a
+// whole file when hunks cannot be applied separately (new, deleted, binary or
+// mode changes)
+type Change struct {
+	ID   string
+	File string
+	// Hunk is nil for whole-file changes
+	Hunk *git.Hunk
+	// Diff is the text describing the change to the model
+	Diff string
+}
+
+// Label is a short description of the change for listings
+func (c Change) Label() string {
+	if c.Hunk == nil {
+		return c.File
+	}
+	return fmt.Sprintf("%s @@ -%d,%d +%d,%d @@", c.File, c.Hunk.OldStart, c.Hunk.OldLines, c.Hunk.NewStart, c.Hunk.NewLines)
+}
+
+// Group is one commit of the split
+type Group struct {
+	Message string
+	Changes []Change
+	// Skip leaves the group's changes uncommitted
+	Skip bool
+}
+
+// Collect returns the pending changes of the repository, staged or not, in
+// a stable order
+func Collect() ([]Change, error) {
+	entries, err := git.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	add := func(c Change) {
+		c.ID = fmt.Sprintf("C%d", len(changes)+1)
+		changes = append(changes, c)
+	}
+
+	for _, e := range entries {
+		switch {
+		case e.IsConflicted():
+			return nil, fmt.Errorf("%s has merge conflicts; resolve them before splitting", e.Path)
+		case e.IsUntracked():
+			add(Change{File: e.Path, Diff: newFilePreview(e.Path)})
+			continue
+		case e.Kind == git.EntryIgnored:
+			continue
+		}
+
+		paths := []string{e.Path}
+		if e.Kind == git.EntryRenamed {
+			// Without rename detection a rename is a deletion plus a new file
+			paths = append(paths, e.OrigPath)
+		}
+		for _, path := range paths {
+			fileChanges, err := collectFile(path, e.Submodule)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range fileChanges {
+				add(c)
+			}
+		}
+	}
+	return changes, nil
+}
+
+func collectFile(path string, submodule bool) ([]Change, error) {
+	diff, err := git.GetDiffAgainstHead(path)
+	if err != nil {
+		return nil, err
+	}
+	if diff == "" {
+		return nil, nil
+	}
+
+	fd, err := git.ParseFileDiff(diff)
+	if err != nil {
+		return nil, err
+	}
+	if submodule || wholeFile(fd) {
+		return []Change{{File: path, Diff: diff}}, nil
+	}
+
+	header := strings.Join(fd.Header, "\n")
+	changes := make([]Change, 0, len(fd.Hunks))
+	for i := range fd.Hunks {
+		h := fd.Hunks[i]
+		changes = append(changes, Change{
+			File: path,
+			Hunk: &h,
+			Diff: header + "\n" + h.Header() + "\n" + strings.Join(h.Lines, "\n"),
+		})
+	}
+	return changes, nil
+}
+
+// wholeFile reports whether the diff must be staged as a unit
+func wholeFile(fd *git.FileDiff) bool {
+	if len(fd.Hunks) == 0 {
+		return true
+	}
+	for _, line := range fd.Header {
+		for _, prefix := range []string{"new file mode", "deleted file mode", "old mode", "Binary files"} {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newFilePreview(path string) string {
+	top, err := git.TopLevel()
+	if err != nil {
+		return "new file " + path
+	}
+	content, err := os.ReadFile(filepath.Join(top, path))
+	if err != nil {
+		return "new file " + path
+	}
+	if strings.ContainsRune(string(content), 0) {
+		return "new binary file " + path
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	preview := fmt.Sprintf("new file %s (%d lines)\n", path, len(lines))
+	if len(lines) > maxPreviewLines {
+		lines = append(lines[:maxPreviewLines], "...")
+	}
+	for _, line := range lines {
+		preview += "+" + line + "\n"
+	}
+	return preview
+}
+
+// Describe formats the changes for the model
+func Describe(changes []Change) string {
+	var b strings.Builder
+	for _, c := range changes {
+		fmt.Fprintf(&b, "### %s: %s\n```diff\n%s\n```\n\n", c.ID, c.Label(), strings.TrimRight(c.Diff, "\n"))
+	}
+	return b.String()
+}
+
+// Arrange builds groups from planned commits given as messages and change
+// IDs. Unknown IDs are ignored, a change claimed twice stays in the first
+// group, and changes the plan left out are collected in a final group whose
+// message follows style.
+func Arrange(changes []Change, messages []string, ids [][]string, style config.Style) []Group {
+	byID := make(map[string]Change, len(changes))
+	for _, c := range changes {
+		byID[c.ID] = c
+	}
+
+	used := make(map[string]bool)
+	var groups []Group
+	for i, msg := range messages {
+		group := Group{Message: msg}
+		for _, id := range ids[i] {
+			id = strings.TrimSpace(id)
+			if c, ok := byID[id]; ok && !used[id] {
+				used[id] = true
+				group.Changes = append(group.Changes, c)
+			}
+		}
+		if len(group.Changes) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	var rest []Change
+	for _, c := range changes {
+		if !used[c.ID] {
+			rest = append(rest, c)
+		}
+	}
+	if len(rest) > 0 {
+		remaining := message.CommitMessage{Type: "chore", Subject: "include remaining changes"}
+		groups = append(groups, Group{Message: remaining.Render(style), Changes: rest})
+	}
+	return groups
+}
+
+// Apply unstages everything and then stages and commits each group that is
+// not skipped, in order. It returns the number of commits created. On error
+// HEAD and the index are restored to their state before the split.
+func Apply(groups []Group) (int, error) {
+	// An unborn branch has no HEAD to return to
+	head, _ := git.RevParse("HEAD")
+	tree, err := git.WriteTree()
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := commitGroups(groups)
+	if err != nil {
+		if restoreErr := restore(head, tree, created); restoreErr != nil {
+			return created, fmt.Errorf("%w; undoing the split also failed: %v", err, restoreErr)
+		}
+		return 0, err
+	}
+	return created, nil
+}
+
+func commitGroups(groups []Group) (int, error) {
+	if err := git.ResetIndex(); err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, g := range groups {
+		if g.Skip {
+			continue
+		}
+		if err := stage(g.Changes); err != nil {
+			return created, fmt.Errorf("commit %d: %w", created+1, err)
+		}
+		if err := git.CreateCommit(g.Message); err != nil {
+			return created, fmt.Errorf("commit %d: failed to create commit: %w", created+1, err)
+		}
+		created++
+	}
+	return created, nil
+}
+
+// restore moves HEAD back to head when commits were made and reloads the
+// index snapshot. The working tree is never touched by a split.
+func restore(head, tree string, created int) error {
+	if created > 0 {
+		var err error
+		if head == "" {
+			err = git.DeleteHead()
+		} else {
+			err = git.SoftReset(head)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return git.ReadTree(tree)
+}
+
+// stage adds the changes to the index. Hunks are matched by content against
+// the current unstaged diff, since earlier commits shift their line numbers.
+func stage(changes []Change) error {
+	var whole []string
+	hunks := make(map[string][]*git.Hunk)
+	var order []string
+	for _, c := range changes {
+		if c.Hunk == nil {
+			whole = append(whole, c.File)
+			continue
+		}
+		if _, ok := hunks[c.File]; !ok {
+			order = append(order, c.File)
+		}
+		hunks[c.File] = append(hunks[c.File], c.Hunk)
+	}
+
+	if err := git.StagePaths(whole); err != nil {
+		return err
+	}
+
+	for _, file := range order {
+		diff, err := git.GetFileDiff(file, false)
+		if err != nil {
+			return err
+		}
+		fd, err := git.ParseFileDiff(diff)
+		if err != nil {
+			return err
+		}
+
+		var indexes []int
+		for _, want := range hunks[file] {
+			i := findHunk(fd, want)
+			if i < 0 {
+				return fmt.Errorf("could not find hunk %s in %s; the file changed since planning", want.Header(), file)
+			}
+			indexes = append(indexes, i)
+		}
+		if err := git.ApplyPatchToIndex(fd.Patch(indexes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findHunk(fd *git.FileDiff, want *git.Hunk) int {
+	target := strings.Join(want.Lines, "\n")
+	for i, h := range fd.Hunks {
+		if strings.Join(h.Lines, "\n") == target {
+			return i
+		}
+	}
+	return -1
+}
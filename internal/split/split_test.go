@@ -0,0 +1,106 @@
+package split
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/git"
+	"github.com/bitcs/commet/internal/testutil"
+)
+
+func TestArrangeRemainingMessageFollowsStyle(t *testing.T) {
+	changes := []Change{{ID: "C1", File: "a.go"}, {ID: "C2", File: "b.go"}}
+
+	tests := map[config.Style]string{
+		config.StyleConventional: "chore: include remaining changes",
+		config.StyleSimple:       "Include remaining changes",
+	}
+	for style, want := range tests {
+		groups := Arrange(changes, []string{"feat: add a"}, [][]string{{"C1", "C9"}}, style)
+		if len(groups) != 2 {
+			t.Fatalf("%s: got %d groups, want 2", style, len(groups))
+		}
+		if got := groups[1].Message; got != want {
+			t.Errorf("%s: remaining message = %q, want %q", style, got, want)
+		}
+		if len(groups[1].Changes) != 1 || groups[1].Changes[0].ID != "C2" {
+			t.Errorf("%s: remaining changes = %+v, want C2", style, groups[1].Changes)
+		}
+	}
+}
+
+func TestCollectOnUnbornBranch(t *testing.T) {
+	testutil.NewRepo(t)
+	testutil.WriteFile(t, "staged.txt", "staged\n")
+	testutil.WriteFile(t, "new.txt", "new\n")
+	testutil.Git(t, "add", "staged.txt")
+
+	changes, err := Collect()
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	var labels []string
+	for _, c := range changes {
+		labels = append(labels, c.Label())
+	}
+	if strings.Join(labels, ",") != "staged.txt,new.txt" {
+		t.Errorf("Collect() = %v, want staged.txt and new.txt", labels)
+	}
+
+	groups := Arrange(changes, []string{"feat: add files"}, [][]string{{"C1", "C2"}}, config.StyleConventional)
+	if created, err := Apply(groups); err != nil || created != 1 {
+		t.Fatalf("Apply() = %d, %v, want 1 commit", created, err)
+	}
+	if got := testutil.Git(t, "show", "--name-only", "--format=%s", "HEAD"); got != "feat: add files\n\nnew.txt\nstaged.txt" {
+		t.Errorf("HEAD = %q", got)
+	}
+}
+
+func TestApplyRestoresStateOnError(t *testing.T) {
+	testutil.NewRepo(t)
+	testutil.WriteFile(t, "a.txt", "a\n")
+	testutil.WriteFile(t, "b.txt", "b\n")
+	testutil.Git(t, "add", ".")
+	testutil.Git(t, "commit", "-q", "-m", "initial")
+	head := testutil.Git(t, "rev-parse", "HEAD")
+
+	testutil.WriteFile(t, "a.txt", "a changed\n")
+	testutil.WriteFile(t, "b.txt", "b changed\n")
+	testutil.Git(t, "add", "b.txt")
+	index := testutil.Git(t, "write-tree")
+
+	changes, err := Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Collect() returned %d changes, want 2", len(changes))
+	}
+
+	// The second group refers to a hunk that is no longer in the diff
+	stale := changes[1]
+	stale.Hunk = &git.Hunk{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1, Lines: []string{"-x", "+y"}}
+	groups := []Group{
+		{Message: "fix: change a", Changes: changes[:1]},
+		{Message: "fix: change b", Changes: []Change{stale}},
+	}
+
+	created, err := Apply(groups)
+	if err == nil {
+		t.Fatal("Apply() succeeded, want error")
+	}
+	if created != 0 {
+		t.Errorf("Apply() created = %d, want 0 after undoing the split", created)
+	}
+	if got := testutil.Git(t, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD = %s, want %s", got, head)
+	}
+	if got := testutil.Git(t, "write-tree"); got != index {
+		t.Errorf("index tree = %s, want the staged snapshot %s", got, index)
+	}
+	if got := testutil.Git(t, "status", "--porcelain"); got != "M a.txt\nM  b.txt" {
+		t.Errorf("status = %q, want a.txt unstaged and b.txt staged", got)
+	}
+}
@@ -1,11 +1,13 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/atotto/clipboard"
 	"github.com/bitcs/commet/internal/config"
+	"github.com/bitcs/commet/internal/llm"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -33,6 +35,13 @@ type configModel struct {
 	previousState configState
 	hasChanges    bool
 	modelOptions  []string
+	loadingModels bool
+}
+
+// modelsLoadedMsg carries the model list fetched for the config TUI
+type modelsLoadedMsg struct {
+	list llm.ModelList
+	err  error
 }
 
 func NewConfigModel(cfg *config.Config) configModel {
@@ -65,6 +74,16 @@ func (m configModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case selectingModel:
 			return m.updateModelSelection(msg)
 		}
+	case modelsLoadedMsg:
+		if m.state != selectingModel || msg.list.Provider != m.config.AI.Provider {
+			return m, nil
+		}
+		m.loadingModels = false
+		m.modelOptions = msg.list.Models
+		if msg.err != nil {
+			m.message = "Showing built-in models (provider not reachable)"
+		}
+		m.cursor = m.currentModelIndex()
 	}
 	return m, nil
 }
@@ -101,7 +120,7 @@ func (m configModel) updateMainMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m configModel) updateAISettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	aiItems := []string{"Provider (press Enter to cycle)", "API Key (press Enter to edit)", "Model (press Enter to edit)", "← Back"}
+	aiItems := []string{"Provider (press Enter to cycle)", "API Key (press Enter to edit)", "Model (press Enter to edit)", "Style (press Enter to cycle)", "← Back"}
 
 	switch msg.String() {
 	case "ctrl+c", "q":
@@ -132,20 +151,12 @@ func (m configModel) updateAISettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.modelOptions = m.config.AI.GetAvailableModels()
 			m.previousState = aiSettings
 			m.state = selectingModel
-			m.cursor = 0
-			// Find current model index
-			currentModel := m.config.AI.Model
-			if currentModel == "" {
-				currentModel = m.config.GetDefaultModel()
-			}
-			for i, model := range m.modelOptions {
-				if model == currentModel {
-					m.cursor = i
-					break
-				}
-			}
-			return m, nil
+			m.cursor = m.currentModelIndex()
+			m.loadingModels = true
+			return m, m.loadModels()
 		case 3:
+			return m.selectStyle()
+		case 4:
 			m.state = mainMenu
 			m.cursor = 0
 		}
@@ -153,8 +164,25 @@ func (m configModel) updateAISettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m configModel) selectStyle() (tea.Model, tea.Cmd) {
+	styles := []config.Style{config.StyleConventional, config.StyleSimple, config.StyleDetailed}
+	currentIndex := 0
+	for i, st := range styles {
+		if st == m.config.AI.Style {
+			currentIndex = i
+			break
+		}
+	}
+
+	m.config.AI.Style = styles[(currentIndex+1)%len(styles)]
+	m.message = fmt.Sprintf("Style changed to %s", m.config.AI.Style)
+	m.hasChanges = true
+
+	return m, nil
+}
+
 func (m configModel) selectProvider() (tea.Model, tea.Cmd) {
-	providers := []config.Provider{config.ProviderOpenAI, config.ProviderClaude, config.ProviderGoogle, config.ProviderGroq}
+	providers := config.Providers()
 	currentIndex := 0
 	for i, p := range providers {
 		if p == m.config.AI.Provider {
@@ -164,7 +192,7 @@ func (m configModel) selectProvider() (tea.Model, tea.Cmd) {
 	}
 
 	nextIndex := (currentIndex + 1) % len(providers)
-	m.config.AI.Provider = providers[nextIndex]
+	m.config.AI.SetProvider(providers[nextIndex])
 
 	m.config.AI.Model = ""
 
@@ -225,6 +253,31 @@ func (m configModel) updateGitSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// loadModels fetches the provider's model list in the background
+func (m configModel) loadModels() tea.Cmd {
+	cfg := m.config
+	provider := cfg.AI.Provider
+	return func() tea.Msg {
+		list, err := llm.ListModels(context.Background(), cfg, provider, false)
+		return modelsLoadedMsg{list: list, err: err}
+	}
+}
+
+// currentModelIndex returns the position of the configured model in the
+// model options, or 0 if it is not listed
+func (m configModel) currentModelIndex() int {
+	currentModel := m.config.AI.Model
+	if currentModel == "" {
+		currentModel = m.config.GetDefaultModel()
+	}
+	for i, model := range m.modelOptions {
+		if model == currentModel {
+			return i
+		}
+	}
+	return 0
+}
+
 func (m configModel) updateModelSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
@@ -291,7 +344,7 @@ func (m configModel) updateTextInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m configModel) applyTextInput() {
 	switch m.currentField {
 	case "API Key":
-		m.config.AI.APIKey = m.textInput
+		m.config.AI.SetAPIKey(m.textInput)
 		if m.textInput == "" {
 			m.message = "API Key cleared"
 		} else {
@@ -369,6 +422,7 @@ func (m configModel) View() string {
 			fmt.Sprintf("Provider: %s", m.config.AI.Provider),
 			fmt.Sprintf("API Key: %s", m.config.MaskAPIKey()),
 			fmt.Sprintf("Model: %s", m.getDisplayModel()),
+			fmt.Sprintf("Style: %s", m.config.AI.Style),
 			"← Back",
 		}
 
@@ -424,6 +478,9 @@ func (m configModel) View() string {
 
 	case selectingModel:
 		s.WriteString("Select Model:\n\n")
+		if m.loadingModels {
+			s.WriteString("Fetching models from provider...\n\n")
+		}
 
 		for i, model := range m.modelOptions {
 			cursor := " "
@@ -440,7 +497,11 @@ func (m configModel) View() string {
 
 	case confirmingSave:
 		s.WriteString("Save Commet Configuration?\n\n")
-		s.WriteString("Your changes will be saved to ~/.commet.yaml\n\n")
+		path, err := config.FilePath()
+		if err != nil {
+			path = "the config file"
+		}
+		s.WriteString(fmt.Sprintf("Your changes will be saved to %s\n\n", path))
 		s.WriteString("Press 'y' or Enter to save, 'n' or Esc to cancel")
 	}
 
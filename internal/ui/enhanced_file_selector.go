@@ -10,6 +10,7 @@ import (
 )
 
 type enhancedFileModel struct {
+	repo          git.Repository
 	files         []string
 	cursor        int
 	selected      map[int]bool
@@ -22,6 +23,12 @@ type enhancedFileModel struct {
 	fileStatus    map[string]string // Maps filename to status (staged, unstaged, untracked)
 	initialStaged map[string]bool   // Track which files were initially staged
 	quitted       bool              // Track if user quit the selection
+
+	// Hunk mode steps through the unstaged hunks of the focused file
+	hunkMode     bool
+	hunkDiff     *git.FileDiff
+	hunkCursor   int
+	hunkSelected map[string]map[int]bool // Maps filename to the chosen hunk indexes
 }
 
 var (
@@ -76,58 +83,39 @@ var (
 		Bold(true)
 )
 
-func NewEnhancedFileSelector(files []string, stagedFiles, unstagedFiles, untrackedFiles []string) enhancedFileModel {
+// NewEnhancedFileSelector lists the paths of a single git status read,
+// pre-selecting those with staged changes
+func NewEnhancedFileSelector(repo git.Repository, entries []git.StatusEntry) enhancedFileModel {
+	files := make([]string, 0, len(entries))
 	fileStatus := make(map[string]string)
 	initialStaged := make(map[string]bool)
 	selected := make(map[int]bool)
-	
-	// Create sets for quick lookup
-	stagedSet := make(map[string]bool)
-	unstagedSet := make(map[string]bool)
-	untrackedSet := make(map[string]bool)
-	
-	for _, file := range stagedFiles {
-		stagedSet[file] = true
-	}
-	for _, file := range unstagedFiles {
-		unstagedSet[file] = true
-	}
-	for _, file := range untrackedFiles {
-		untrackedSet[file] = true
-	}
-	
-	// Map file status and pre-select staged files
-	// Handle each file in the combined list
-	for i, file := range files {
-		// Determine primary status with clear priority
-		isStaged := stagedSet[file]
-		isUnstaged := unstagedSet[file]
-		isUntracked := untrackedSet[file]
-		
-		if isStaged {
+
+	for _, e := range entries {
+		i := len(files)
+		switch {
+		case e.IsStaged():
 			// File has staged changes - show as staged and pre-select
-			fileStatus[file] = "staged"
-			initialStaged[file] = true
+			fileStatus[e.Path] = "staged"
+			initialStaged[e.Path] = true
 			selected[i] = true
-		} else if isUnstaged {
-			// File has only unstaged changes - show as modified, don't pre-select
-			fileStatus[file] = "unstaged"
-			// Don't pre-select unstaged files
-		} else if isUntracked {
-			// File is untracked - show as new, don't pre-select  
-			fileStatus[file] = "untracked"
-			// Don't pre-select untracked files
-		} else {
-			// This shouldn't happen if our file lists are correct
-			fileStatus[file] = "unknown"
+		case e.IsUnstaged():
+			fileStatus[e.Path] = "unstaged"
+		case e.IsUntracked():
+			fileStatus[e.Path] = "untracked"
+		default:
+			continue
 		}
+		files = append(files, e.Path)
 	}
-	
+
 	return enhancedFileModel{
+		repo:          repo,
 		files:         files,
 		selected:      selected,
 		fileStatus:    fileStatus,
 		initialStaged: initialStaged,
+		hunkSelected:  make(map[string]map[int]bool),
 	}
 }
 
@@ -144,11 +132,19 @@ func (m enhancedFileModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		diffStyle = diffStyle.Width(msg.Width - 50) // Leave space for file list + margins
 
 	case tea.KeyMsg:
+		if m.hunkMode {
+			return m.updateHunkMode(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.quitted = true
 			return m, tea.Quit
 
+		case "tab":
+			// Choose individual hunks of the focused file
+			return m, m.loadHunksForCurrentFile()
+
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
@@ -162,8 +158,9 @@ func (m enhancedFileModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case " ":
-			// Toggle selection
+			// Toggle selection; whole-file selection replaces any hunk choice
 			m.selected[m.cursor] = !m.selected[m.cursor]
+			delete(m.hunkSelected, m.files[m.cursor])
 
 		case "enter":
 			// Confirm selection
@@ -190,6 +187,24 @@ func (m enhancedFileModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case hunksLoadedMsg:
+		if msg.err != nil || len(msg.diff.Hunks) == 0 {
+			text := "No unstaged hunks in " + msg.file + " (untracked and fully staged files are selected as a whole)"
+			if msg.err != nil {
+				text = "Error loading hunks: " + msg.err.Error()
+			}
+			m.diffLines = strings.Split(text, "\n")
+			m.diffScroll = 0
+			return m, nil
+		}
+		m.hunkMode = true
+		m.hunkDiff = msg.diff
+		m.hunkCursor = 0
+		if m.hunkSelected[msg.file] == nil {
+			m.hunkSelected[msg.file] = make(map[int]bool)
+		}
+		m.renderHunks()
+
 	case diffLoadedMsg:
 		m.currentDiff = string(msg)
 		m.diffLines = strings.Split(string(msg), "\n")
@@ -215,7 +230,9 @@ func (m enhancedFileModel) View() string {
 		}
 
 		checkbox := "☐ "
-		if m.selected[i] {
+		if len(m.hunkSelected[file]) > 0 {
+			checkbox = "◐ "
+		} else if m.selected[i] {
 			checkbox = "☑ "
 		}
 
@@ -245,14 +262,21 @@ func (m enhancedFileModel) View() string {
 		fileListContent += line + "\n"
 	}
 
+	help := "Space: select, Tab: hunks, Enter: confirm\nh/l: scroll diff, q: quit\n● staged ◯ modified ✦ added ◐ partial"
+	if m.hunkMode {
+		help = "j/k: next/prev hunk, Space: toggle hunk\nTab/Enter/Esc: back to files, q: quit"
+	}
 	fileListContent += "\n" + lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
-		Render("Space: select, Enter: confirm\nh/l: scroll diff, q: quit\n● staged ◯ modified ✦ added")
+		Render(help)
 
 	fileList := listStyle.Render(fileListContent)
 
 	// Diff section
 	diffContent := headerStyle.Render("File Changes")
+	if m.hunkMode {
+		diffContent = headerStyle.Render(fmt.Sprintf("Hunks in %s (%d/%d)", m.files[m.cursor], m.hunkCursor+1, len(m.hunkDiff.Hunks)))
+	}
 	
 	if m.loadingDiff {
 		diffContent += "\n\nLoading diff..."
@@ -300,7 +324,12 @@ func (m enhancedFileModel) formatDiffLines(lines []string) string {
 	var formatted strings.Builder
 
 	for i, line := range lines {
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+		if strings.HasPrefix(line, "❯ ") {
+			// Focused hunk header in hunk mode
+			formatted.WriteString(cursorStyle.Render(line))
+		} else if strings.HasPrefix(line, "  ☐ ") || strings.HasPrefix(line, "  ☑ ") {
+			formatted.WriteString(hunkStyle.Render(line))
+		} else if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
 			// Added lines in muted green
 			formatted.WriteString(addedStyle.Render(line))
 		} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
@@ -332,7 +361,7 @@ func (m enhancedFileModel) loadDiffForCurrentFile() tea.Cmd {
 	m.loadingDiff = true
 
 	return tea.Cmd(func() tea.Msg {
-		diff, err := git.GetSingleFileDiff(filename)
+		diff, err := m.repo.GetSingleFileDiff(filename)
 		if err != nil {
 			return diffLoadedMsg("Error loading diff: " + err.Error())
 		}
@@ -342,6 +371,102 @@ func (m enhancedFileModel) loadDiffForCurrentFile() tea.Cmd {
 
 type diffLoadedMsg string
 
+type hunksLoadedMsg struct {
+	file string
+	diff *git.FileDiff
+	err  error
+}
+
+func (m enhancedFileModel) loadHunksForCurrentFile() tea.Cmd {
+	if len(m.files) == 0 || m.cursor >= len(m.files) {
+		return nil
+	}
+
+	filename := m.files[m.cursor]
+	return func() tea.Msg {
+		diff, err := m.repo.GetFileDiff(filename, false)
+		if err != nil {
+			return hunksLoadedMsg{file: filename, err: err}
+		}
+		fd, err := git.ParseFileDiff(diff)
+		return hunksLoadedMsg{file: filename, diff: fd, err: err}
+	}
+}
+
+func (m enhancedFileModel) updateHunkMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	file := m.files[m.cursor]
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.quitted = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.hunkCursor > 0 {
+			m.hunkCursor--
+		}
+
+	case "down", "j":
+		if m.hunkCursor < len(m.hunkDiff.Hunks)-1 {
+			m.hunkCursor++
+		}
+
+	case " ":
+		m.hunkSelected[file][m.hunkCursor] = !m.hunkSelected[file][m.hunkCursor]
+		if !m.hunkSelected[file][m.hunkCursor] {
+			delete(m.hunkSelected[file], m.hunkCursor)
+		}
+
+	case "left", "h":
+		if m.diffScroll > 0 {
+			m.diffScroll--
+		}
+		return m, nil
+
+	case "right", "l":
+		if m.diffScroll < len(m.diffLines)-1 {
+			m.diffScroll++
+		}
+		return m, nil
+
+	case "tab", "enter", "esc":
+		m.hunkMode = false
+		if len(m.hunkSelected[file]) > 0 {
+			m.selected[m.cursor] = true
+		} else {
+			delete(m.hunkSelected, file)
+		}
+		return m, m.loadDiffForCurrentFile()
+	}
+
+	m.renderHunks()
+	return m, nil
+}
+
+// renderHunks lays out the hunks of the focused file with their selection
+// state and scrolls to the current hunk
+func (m *enhancedFileModel) renderHunks() {
+	chosen := m.hunkSelected[m.files[m.cursor]]
+	m.diffLines = nil
+
+	for i, h := range m.hunkDiff.Hunks {
+		if i == m.hunkCursor {
+			m.diffScroll = len(m.diffLines)
+		}
+
+		prefix := "  "
+		if i == m.hunkCursor {
+			prefix = "❯ "
+		}
+		checkbox := "☐ "
+		if chosen[i] {
+			checkbox = "☑ "
+		}
+		m.diffLines = append(m.diffLines, prefix+checkbox+h.Header())
+		m.diffLines = append(m.diffLines, h.Lines...)
+	}
+}
+
 func (m enhancedFileModel) GetSelectedFiles() []string {
 	var selected []string
 	for i, isSelected := range m.selected {
@@ -366,21 +491,60 @@ func (m enhancedFileModel) GetFilesToUnstage() []string {
 	return toUnstage
 }
 
-func RunEnhancedFileSelector(files, stagedFiles, unstagedFiles, untrackedFiles []string) ([]string, []string, error) {
-	model := NewEnhancedFileSelector(files, stagedFiles, unstagedFiles, untrackedFiles)
+// GetHunkPatches returns, for each file with individually chosen hunks, a patch
+// staging only those hunks
+func (m enhancedFileModel) GetHunkPatches() (map[string]string, error) {
+	patches := make(map[string]string)
+	for file, chosen := range m.hunkSelected {
+		if len(chosen) == 0 {
+			continue
+		}
+
+		// Re-read the diff so the patch matches the index at confirmation time
+		diff, err := m.repo.GetFileDiff(file, false)
+		if err != nil {
+			return nil, err
+		}
+		fd, err := git.ParseFileDiff(diff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse diff for %s: %w", file, err)
+		}
+
+		var indexes []int
+		for i := range fd.Hunks {
+			if chosen[i] {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) != len(chosen) {
+			return nil, fmt.Errorf("the hunks of %s changed during selection", file)
+		}
+		patches[file] = fd.Patch(indexes)
+	}
+	return patches, nil
+}
+
+// RunEnhancedFileSelector returns the selected files, the initially staged
+// files to unstage and patches for files where only some hunks were chosen
+func RunEnhancedFileSelector(repo git.Repository, entries []git.StatusEntry) ([]string, []string, map[string]string, error) {
+	model := NewEnhancedFileSelector(repo, entries)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	
 	finalModel, err := p.Run()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if m, ok := finalModel.(enhancedFileModel); ok {
 		if m.quitted {
-			return nil, nil, fmt.Errorf("user cancelled file selection")
+			return nil, nil, nil, fmt.Errorf("user cancelled file selection")
+		}
+		patches, err := m.GetHunkPatches()
+		if err != nil {
+			return nil, nil, nil, err
 		}
-		return m.GetSelectedFiles(), m.GetFilesToUnstage(), nil
+		return m.GetSelectedFiles(), m.GetFilesToUnstage(), patches, nil
 	}
 
-	return nil, nil, fmt.Errorf("unexpected model type")
+	return nil, nil, nil, fmt.Errorf("unexpected model type")
 }
\ No newline at end of file
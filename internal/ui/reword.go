@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/reword"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// rewordMessageStyle frames the full proposed message of the selected commit
+var rewordMessageStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("240")).
+	Padding(0, 1)
+
+type rewordModel struct {
+	candidates []reword.Candidate
+	cursor     int
+	editing    bool
+	textInput  string
+	confirmed  bool
+	quitted    bool
+}
+
+func newRewordModel(candidates []reword.Candidate) rewordModel {
+	return rewordModel{candidates: candidates}
+}
+
+func (m rewordModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m rewordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.editing {
+		return m.updateEditing(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		m.quitted = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.candidates)-1 {
+			m.cursor++
+		}
+
+	case " ", "x":
+		m.candidates[m.cursor].Approved = !m.candidates[m.cursor].Approved
+
+	case "a":
+		// Approve everything, or nothing when everything already is
+		all := true
+		for _, c := range m.candidates {
+			all = all && c.Approved
+		}
+		for i := range m.candidates {
+			m.candidates[i].Approved = !all
+		}
+
+	case "e":
+		m.editing = true
+		m.textInput, _, _ = strings.Cut(m.candidates[m.cursor].Message, "\n")
+
+	case "enter":
+		m.confirmed = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m rewordModel) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitted = true
+		return m, tea.Quit
+	case "esc":
+		m.editing = false
+	case "enter":
+		if subject := strings.TrimSpace(m.textInput); subject != "" {
+			c := &m.candidates[m.cursor]
+			_, body, _ := strings.Cut(c.Message, "\n")
+			c.Message = subject
+			if body != "" {
+				c.Message += "\n" + body
+			}
+			c.Approved = true
+		}
+		m.editing = false
+	case "backspace", "ctrl+h":
+		if runes := []rune(m.textInput); len(runes) > 0 {
+			m.textInput = string(runes[:len(runes)-1])
+		}
+	case "ctrl+u":
+		m.textInput = ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.textInput += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m rewordModel) View() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Reword %d commits", len(m.candidates))) + "\n\n")
+
+	for i, c := range m.candidates {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "❯ "
+		}
+		checkbox := "☐ "
+		if c.Approved {
+			checkbox = "☑ "
+		}
+
+		hash := c.Commit.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		line := fmt.Sprintf("%s%s%s %s", cursor, checkbox, hash, subject)
+		if i == m.cursor {
+			line = cursorStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+		b.WriteString(splitSkippedStyle.Render(fmt.Sprintf("          %s", c.Commit.Subject)))
+		b.WriteString(splitChangeStyle.Render(fmt.Sprintf("  (%s)", c.Reason)) + "\n")
+	}
+
+	// Approval covers the whole message, so show the body and footers too
+	if len(m.candidates) > 0 {
+		message := strings.TrimRight(m.candidates[m.cursor].Message, "\n")
+		b.WriteString("\n" + rewordMessageStyle.Render(message) + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.editing {
+		b.WriteString(fmt.Sprintf("Subject: %s█\n", m.textInput))
+		b.WriteString(splitChangeStyle.Render("Enter: save, Esc: cancel"))
+	} else {
+		b.WriteString(splitChangeStyle.Render("j/k: move, Space: approve, a: approve all, e: edit subject\nEnter: rewrite approved, q: quit"))
+	}
+	return b.String()
+}
+
+// RunRewordReview shows the proposed messages next to the old ones and
+// returns the candidates with the user's approvals and edits
+func RunRewordReview(candidates []reword.Candidate) ([]reword.Candidate, error) {
+	p := tea.NewProgram(newRewordModel(candidates), tea.WithAltScreen())
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := finalModel.(rewordModel); ok {
+		if m.quitted || !m.confirmed {
+			return nil, fmt.Errorf("user cancelled reword")
+		}
+		return m.candidates, nil
+	}
+
+	return nil, fmt.Errorf("unexpected model type")
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/split"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type splitModel struct {
+	groups    []split.Group
+	cursor    int
+	editing   bool
+	textInput string
+	confirmed bool
+	quitted   bool
+}
+
+var (
+	splitChangeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("240"))
+
+	splitSkippedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("240")).
+				Strikethrough(true)
+)
+
+func newSplitModel(groups []split.Group) splitModel {
+	return splitModel{groups: groups}
+}
+
+func (m splitModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m splitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.editing {
+		return m.updateEditing(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		m.quitted = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.groups)-1 {
+			m.cursor++
+		}
+
+	case "shift+up", "K":
+		// Move the focused commit earlier
+		if m.cursor > 0 {
+			m.groups[m.cursor-1], m.groups[m.cursor] = m.groups[m.cursor], m.groups[m.cursor-1]
+			m.cursor--
+		}
+
+	case "shift+down", "J":
+		if m.cursor < len(m.groups)-1 {
+			m.groups[m.cursor+1], m.groups[m.cursor] = m.groups[m.cursor], m.groups[m.cursor+1]
+			m.cursor++
+		}
+
+	case " ", "x":
+		m.groups[m.cursor].Skip = !m.groups[m.cursor].Skip
+
+	case "e":
+		m.editing = true
+		m.textInput, _, _ = strings.Cut(m.groups[m.cursor].Message, "\n")
+
+	case "enter":
+		m.confirmed = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m splitModel) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitted = true
+		return m, tea.Quit
+	case "esc":
+		m.editing = false
+	case "enter":
+		if subject := strings.TrimSpace(m.textInput); subject != "" {
+			_, body, _ := strings.Cut(m.groups[m.cursor].Message, "\n")
+			m.groups[m.cursor].Message = subject
+			if body != "" {
+				m.groups[m.cursor].Message += "\n" + body
+			}
+		}
+		m.editing = false
+	case "backspace", "ctrl+h":
+		if runes := []rune(m.textInput); len(runes) > 0 {
+			m.textInput = string(runes[:len(runes)-1])
+		}
+	case "ctrl+u":
+		m.textInput = ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.textInput += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m splitModel) View() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Split into %d commits", len(m.groups))) + "\n\n")
+
+	for i, g := range m.groups {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "❯ "
+		}
+		checkbox := "☑ "
+		if g.Skip {
+			checkbox = "☐ "
+		}
+
+		subject, _, _ := strings.Cut(g.Message, "\n")
+		line := fmt.Sprintf("%s%s%d. %s", cursor, checkbox, i+1, subject)
+		switch {
+		case g.Skip:
+			line = splitSkippedStyle.Render(line)
+		case i == m.cursor:
+			line = cursorStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+
+		for _, c := range g.Changes {
+			b.WriteString(splitChangeStyle.Render("       "+c.Label()) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.editing {
+		b.WriteString(fmt.Sprintf("Subject: %s█\n", m.textInput))
+		b.WriteString(splitChangeStyle.Render("Enter: save, Esc: cancel"))
+	} else {
+		b.WriteString(splitChangeStyle.Render("j/k: move, J/K: reorder, e: edit subject, Space: skip\nEnter: commit all, q: quit"))
+	}
+	return b.String()
+}
+
+// RunSplitPlanner shows the proposed commits for reordering, editing and
+// skipping, and returns the groups in their final order
+func RunSplitPlanner(groups []split.Group) ([]split.Group, error) {
+	p := tea.NewProgram(newSplitModel(groups), tea.WithAltScreen())
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := finalModel.(splitModel); ok {
+		if m.quitted || !m.confirmed {
+			return nil, fmt.Errorf("user cancelled split")
+		}
+		return m.groups, nil
+	}
+
+	return nil, fmt.Errorf("unexpected model type")
+}
@@ -0,0 +1,130 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// Record is one generation request in the usage ledger
+type Record struct {
+	Time         time.Time `json:"time"`
+	Command      string    `json:"command"`
+	Repo         string    `json:"repo"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	Cost         float64   `json:"cost"`
+	Priced       bool      `json:"priced"`
+}
+
+// Summary aggregates records sharing a key
+type Summary struct {
+	Key      string
+	Requests int
+	Usage    Usage
+}
+
+func ledgerPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
+
+// Append adds a record to the ledger
+func Append(r Record) error {
+	path, err := ledgerPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ReadSince returns the ledger records newer than since
+func ReadSince(since time.Time) ([]Record, error) {
+	path, err := ledgerPath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		// Skip lines from interrupted writes instead of failing the report
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if r.Time.After(since) {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// Summarize groups records by key, sorted by descending cost and then by key
+func Summarize(records []Record, key func(Record) string) []Summary {
+	groups := map[string]*Summary{}
+	for _, r := range records {
+		k := key(r)
+		s, ok := groups[k]
+		if !ok {
+			s = &Summary{Key: k}
+			groups[k] = s
+		}
+		s.Requests++
+		s.Usage.Add(Usage{InputTokens: r.InputTokens, OutputTokens: r.OutputTokens, Cost: r.Cost, Priced: r.Priced})
+	}
+
+	summaries := make([]Summary, 0, len(groups))
+	for _, s := range groups {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Usage.Cost != summaries[j].Usage.Cost {
+			return summaries[i].Usage.Cost > summaries[j].Usage.Cost
+		}
+		return summaries[i].Key < summaries[j].Key
+	})
+	return summaries
+}
+
+// ByDay groups records by their local calendar day, oldest first
+func ByDay(records []Record) []Summary {
+	summaries := Summarize(records, func(r Record) string {
+		return r.Time.Local().Format("2006-01-02")
+	})
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Key < summaries[j].Key })
+	return summaries
+}
@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(d int, hour int) time.Time {
+	return time.Date(2024, time.March, d, hour, 0, 0, 0, time.Local)
+}
+
+func TestLedgerRoundTrip(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if records, err := ReadSince(time.Time{}); err != nil || records != nil {
+		t.Fatalf("ReadSince() on a missing ledger = %v, %v, want nothing", records, err)
+	}
+
+	old := Record{Time: day(1, 9), Command: "commit", Model: "gpt-4o", InputTokens: 10}
+	recent := Record{Time: day(3, 9), Command: "pr", Model: "gpt-4o", InputTokens: 20, Cost: 0.5, Priced: true}
+	for _, r := range []Record{old, recent} {
+		if err := Append(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := ReadSince(day(2, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || !records[0].Time.Equal(recent.Time) || records[0].Command != "pr" {
+		t.Errorf("ReadSince() = %+v, want only the recent record", records)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := []Record{
+		{Model: "a", InputTokens: 1, OutputTokens: 2, Cost: 0.1, Priced: true},
+		{Model: "b", InputTokens: 5, Cost: 0.3, Priced: true},
+		{Model: "a", InputTokens: 3, OutputTokens: 4, Cost: 0.1, Priced: true},
+		{Model: "c", InputTokens: 7},
+	}
+
+	got := Summarize(records, func(r Record) string { return r.Model })
+	want := []Summary{
+		{Key: "b", Requests: 1, Usage: Usage{InputTokens: 5, Cost: 0.3, Priced: true}},
+		{Key: "a", Requests: 2, Usage: Usage{InputTokens: 4, OutputTokens: 6, Cost: 0.2, Priced: true}},
+		{Key: "c", Requests: 1, Usage: Usage{InputTokens: 7}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestByDayIsChronological(t *testing.T) {
+	records := []Record{
+		{Time: day(3, 10), Cost: 0.1},
+		{Time: day(1, 10), Cost: 0.5},
+		{Time: day(2, 10), Cost: 0.9},
+		{Time: day(1, 18), Cost: 0.1},
+	}
+
+	var keys []string
+	var requests []int
+	for _, s := range ByDay(records) {
+		keys = append(keys, s.Key)
+		requests = append(requests, s.Requests)
+	}
+	if want := []string{"2024-03-01", "2024-03-02", "2024-03-03"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("ByDay() days = %v, want %v", keys, want)
+	}
+	if want := []int{2, 1, 1}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("ByDay() requests = %v, want %v", requests, want)
+	}
+}
@@ -0,0 +1,122 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitcs/commet/internal/config"
+)
+
+// Usage counts the tokens consumed by one or more requests
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+	// Cost is in USD; it is only meaningful when Priced is set
+	Cost   float64
+	Priced bool
+}
+
+// Add accumulates another usage into u
+func (u *Usage) Add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.Cost += other.Cost
+	u.Priced = u.Priced || other.Priced
+}
+
+// Total returns input plus output tokens
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+func (u Usage) String() string {
+	s := fmt.Sprintf("%d input + %d output tokens", u.InputTokens, u.OutputTokens)
+	if u.Priced {
+		s += fmt.Sprintf(" · $%.4f", u.Cost)
+	}
+	return s
+}
+
+// FromGenerationInfo reads token counts from a langchaingo generation info
+// map. Providers use different keys: OpenAI and Groq report Prompt/Completion
+// tokens, Anthropic Input/Output tokens and Google snake_case counts.
+func FromGenerationInfo(info map[string]any) Usage {
+	return Usage{
+		InputTokens:  firstInt(info, "PromptTokens", "InputTokens", "input_tokens"),
+		OutputTokens: firstInt(info, "CompletionTokens", "OutputTokens", "output_tokens"),
+	}
+}
+
+// defaultPrices are USD per million tokens for the built-in models. Entries
+// match by exact name or by the longest prefix, so dated model versions
+// resolve to their family.
+var defaultPrices = map[string]config.Price{
+	"gpt-4o":                  {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":             {Input: 0.15, Output: 0.60},
+	"gpt-4.1":                 {Input: 2.00, Output: 8.00},
+	"gpt-4.1-mini":            {Input: 0.40, Output: 1.60},
+	"gpt-4.1-nano":            {Input: 0.10, Output: 0.40},
+	"claude-opus-4":           {Input: 15.00, Output: 75.00},
+	"claude-sonnet-4":         {Input: 3.00, Output: 15.00},
+	"claude-3-7-sonnet":       {Input: 3.00, Output: 15.00},
+	"claude-3-5-sonnet":       {Input: 3.00, Output: 15.00},
+	"claude-3-5-haiku":        {Input: 0.80, Output: 4.00},
+	"claude-3-haiku":          {Input: 0.25, Output: 1.25},
+	"gemini-2.5-pro":          {Input: 1.25, Output: 10.00},
+	"gemini-2.5-flash":        {Input: 0.30, Output: 2.50},
+	"gemini-2.0-flash":        {Input: 0.10, Output: 0.40},
+	"gemini-2.0-flash-lite":   {Input: 0.075, Output: 0.30},
+	"llama-3.3-70b-versatile": {Input: 0.59, Output: 0.79},
+	"llama-3.1-8b-instant":    {Input: 0.05, Output: 0.08},
+	"gemma2-9b-it":            {Input: 0.20, Output: 0.20},
+}
+
+// PriceFor returns the price of model, preferring configured overrides
+func PriceFor(cfg *config.AIConfig, model string) (config.Price, bool) {
+	for _, p := range cfg.Prices {
+		if p.Model == model {
+			return p, true
+		}
+	}
+	if p, ok := defaultPrices[model]; ok {
+		return p, true
+	}
+
+	best := ""
+	for prefix := range defaultPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return config.Price{}, false
+	}
+	return defaultPrices[best], true
+}
+
+// WithCost fills in the cost of u for model
+func WithCost(cfg *config.AIConfig, model string, u Usage) Usage {
+	price, ok := PriceFor(cfg, model)
+	if !ok {
+		return u
+	}
+	u.Cost = (float64(u.InputTokens)*price.Input + float64(u.OutputTokens)*price.Output) / 1e6
+	u.Priced = true
+	return u
+}
+
+func firstInt(info map[string]any, keys ...string) int {
+	for _, key := range keys {
+		switch v := info[key].(type) {
+		case int:
+			return v
+		case int32:
+			return int(v)
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+	return 0
+}